@@ -2,9 +2,9 @@ module github.com/roidaradal/needle
 
 go 1.25.4
 
-require github.com/roidaradal/fn v0.5.43
-
 require (
-	golang.org/x/sync v0.18.0 // indirect
-	golang.org/x/text v0.31.0 // indirect
+	github.com/roidaradal/fn v0.5.43
+	golang.org/x/sync v0.18.0
 )
+
+require golang.org/x/text v0.31.0 // indirect
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/roidaradal/needle/pkg/needle"
+)
+
+// Impact command output
+const (
+	impactPackages = "packages" // affected package import paths
+	impactMains    = "mains"    // affected main package import paths
+	impactTests    = "tests"    // import paths of packages with tests to rerun
+	impactJSON     = "json"     // Impact object
+)
+
+// Run impact command: needle impact [options] <modulePath> <package|file>...
+func runImpact(args []string) {
+	cfg := &config{logFormat: "text"}
+	flags := flag.NewFlagSet("impact", flag.ExitOnError)
+	isFiles := flags.Bool("files", false, "arguments are changed files (relative to module path), e.g. from git diff --name-only")
+	output := flags.String("output", impactPackages, "output: packages, mains, tests, or json")
+	flags.BoolVar(&cfg.verbose, "verbose", false, "show debug logs (stage durations, analyzed packages)")
+	flags.Usage = func() {
+		fmt.Println("Usage: needle impact [options] <modulePath> <package|file>...")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	args = flags.Args()
+	if len(args) < 2 && !(*isFiles && len(args) == 1) {
+		flags.Usage()
+		os.Exit(1)
+	}
+	switch *output {
+	case impactPackages, impactMains, impactTests, impactJSON:
+	default:
+		fmt.Printf("Invalid output: %q\n", *output)
+		os.Exit(1)
+	}
+	logger := newLogger(cfg)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	mod, err := needle.Analyze(ctx, args[0],
+		needle.WithLogger(logger),
+		needle.WithoutModuleGraph(),
+		needle.WithoutCallGraph(),
+	)
+	if err != nil {
+		fatal(logger, err)
+	}
+	packages := args[1:]
+	if *isFiles {
+		packages = mod.FilePackages(packages)
+	}
+	impact, err := mod.Impact(packages)
+	if err != nil {
+		fatal(logger, err)
+	}
+
+	var names []string
+	switch *output {
+	case impactJSON:
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(impact)
+		if err != nil {
+			fatal(logger, err)
+		}
+		return
+	case impactMains:
+		names = impact.Mains
+	case impactTests:
+		names = impact.Tests
+	default:
+		names = impact.Packages
+	}
+	for _, name := range names {
+		fmt.Println(mod.PackageImportPath(name))
+	}
+}
@@ -1,123 +0,0 @@
-package needle
-
-import (
-	"fmt"
-	"os"
-	"path/filepath"
-	"slices"
-	"strings"
-
-	"github.com/roidaradal/fn/dict"
-	"github.com/roidaradal/fn/ds"
-	"github.com/roidaradal/fn/io"
-	"github.com/roidaradal/fn/lang"
-	"github.com/roidaradal/fn/list"
-	"github.com/roidaradal/fn/number"
-)
-
-// Create report HTML file from template
-func BuildReport(mod *Module) (string, error) {
-	report := templateHTML
-	// Uncomment this block to test on template.html
-	// report, err := io.ReadFile("template.html")
-	// if err != nil {
-	// 	return err
-	// }
-	// Initialize replacements
-	replacements := dict.StringMap{
-		"ModuleName": mod.Name,
-	}
-	// Apply report decorators
-	decorators := []func(*Module, dict.StringMap){
-		addModReport,
-		addStatsReport,
-		addDepsReport,
-		addCodeReport,
-	}
-	for _, decorator := range decorators {
-		decorator(mod, replacements)
-	}
-	// Replace template placeholders
-	for key, replacement := range replacements {
-		key = templateKey(key)
-		report = strings.ReplaceAll(report, key, replacement)
-	}
-
-	// Build output path (~/.needle/modName.html)
-	path, err := getOutputPath(mod.Name)
-	if err != nil {
-		return "", err
-	}
-
-	// Save report to output file
-	err = io.SaveString(report, path)
-	if err != nil {
-		return "", err
-	}
-	return path, nil
-}
-
-// Build output file path
-func getOutputPath(modName string) (string, error) {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return "", err
-	}
-	path := fmt.Sprintf("%s/.needle/%s.html", filepath.ToSlash(homeDir), modName)
-	err = io.EnsurePathExists(path)
-	if err != nil {
-		return "", err
-	}
-	return path, nil
-}
-
-// Add module report data
-func addModReport(mod *Module, rep dict.StringMap) {
-	var key string
-	table := make([]string, 0)
-	fileCounts := list.Map(mod.Packages, (*Package).FileCount)
-	pkgFileCounts := dict.Entries(dict.Zip(mod.PackageNames(), fileCounts))
-	slices.SortFunc(pkgFileCounts, sortDescCount)
-	lookup := ds.NewLookupCode(mod.Packages)
-	hasTest := mod.Stats.Files[FILE_TEST] > 0
-	for _, e := range pkgFileCounts {
-		pkgName, count := e.Tuple()
-		pkg := lookup[pkgName]
-		node := mod.Nodes[packageToNodeName(pkgName)]
-		table = append(table,
-			"<tr>",
-			wrapTag(td, pkgName),
-			wrapTag(td, percentage(count, mod.Stats.FileCount), withClass(center)),
-			wrapTag(td, number.Comma(count), withClass(center)),
-			lang.Ternary(hasTest,
-				wrapTag(td, fmt.Sprintf("%d | %d", pkg.FileTypes[FILE_CODE], pkg.FileTypes[FILE_TEST]), withClass(center)),
-				"",
-			),
-			wrapTag(td, strings.Join(node.Files, "<br/>"), withClass("mod-files-list hidden")),
-			"</tr>",
-		)
-	}
-	rep["ModPackageCount"] = number.Comma(mod.Stats.PackageCount)
-	for _, pkgType := range []PackageType{PKG_LIB, PKG_MAIN} {
-		key = fmt.Sprintf("%sPackageCount", pkgType)
-		rep[key] = number.Comma(mod.Stats.Packages[pkgType])
-	}
-
-	fileCount := mod.Stats.FileCount
-	rep["ModFileCount"] = number.Comma(fileCount)
-	for _, fileType := range []FileType{FILE_CODE, FILE_TEST} {
-		typeCount := mod.Stats.Files[fileType]
-		key = fmt.Sprintf("%sFileCount", fileType)
-		rep[key] = number.Comma(typeCount)
-
-		key = fmt.Sprintf("%sFileShare", fileType)
-		rep[key] = percentage(typeCount, fileCount)
-	}
-	rep["ModuleTableHeader"] = lang.Ternary(hasTest, wrapTag(th, "Code | Test", withTitle("Code Files | Test Files")), "")
-	rep["ModuleTable"] = strings.Join(table, "")
-}
-
-// Create the template key: %key%
-func templateKey(key string) string {
-	return fmt.Sprintf("%%%s%%", key)
-}
@@ -1,41 +1,312 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
-	"log"
+	"log/slog"
+	"maps"
 	"os"
+	"os/signal"
+	"path"
 	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/roidaradal/fn/io"
-	"github.com/roidaradal/needle/internal/needle"
+	"github.com/roidaradal/needle/pkg/needle"
 )
 
 func main() {
-	modulePath := getArgs()
-	mod, err := needle.BuildModule(modulePath)
-	if err != nil {
-		log.Fatal(err)
+	// Subcommands
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "impact":
+			runImpact(os.Args[2:])
+			return
+		case "why":
+			runWhy(os.Args[2:])
+			return
+		case "vuln":
+			runVuln(os.Args[2:])
+			return
+		}
+	}
+	modulePath, cfg := getArgs()
+	logger := newLogger(cfg)
+	opts := []needle.Option{
+		needle.WithWorkers(cfg.workers),
+		needle.WithTimeout(cfg.timeout),
+		needle.WithLogger(logger),
+		needle.WithCoverProfile(cfg.coverProfile),
+	}
+	if cfg.external {
+		opts = append(opts, needle.WithExternalAnalysis())
 	}
-	outputPath, err := needle.BuildReport(mod)
+	if len(cfg.counts) > 0 {
+		analyzer, err := needle.NewPatternAnalyzer("Counts", cfg.counts)
+		if err != nil {
+			fatal(logger, err)
+		}
+		opts = append(opts, needle.WithAnalyzers(analyzer))
+	}
+	// Verbose logs already show progress, only draw progress bar on terminal
+	var bar *progressBar
+	if !cfg.verbose && isTerminal(os.Stderr) {
+		bar = newProgressBar(os.Stderr)
+		opts = append(opts, needle.WithProgress(bar.draw))
+	}
+
+	// Cancel analysis on Ctrl-C
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	mod, err := needle.Analyze(ctx, modulePath, opts...)
+	bar.finish() // error log starts on its own line
 	if err != nil {
-		log.Fatal(err)
+		fatal(logger, err)
+	}
+	var outputPaths []string
+	if cfg.format == "text" {
+		fmt.Print(needle.BuildTextReport(mod, needle.TextOptions{
+			Color: isColorEnabled(os.Stdout),
+			Width: terminalWidth(os.Stdout),
+		}))
+	} else {
+		outputPaths, err = saveReport(mod, cfg)
+		if err != nil {
+			fatal(logger, err)
+		}
+		logger.Info("report saved", "paths", outputPaths)
+	}
+	// Nested reports are linked from the HTML report only
+	if cfg.external && cfg.format == "html" {
+		externalPaths, err := needle.SaveExternalReports(mod, cfg.html)
+		if err != nil {
+			fatal(logger, err)
+		}
+		logger.Info("external module reports saved", "reports", len(externalPaths))
+	}
+
+	// Call graph export
+	if cfg.callGraph != "" {
+		callGraphPath, err := saveCallGraph(mod, cfg.callGraph)
+		if err != nil {
+			fatal(logger, err)
+		}
+		fmt.Println(callGraphPath)
 	}
-	outputPath, _ = filepath.Abs(outputPath)
 
-	err = io.OpenFile(outputPath)
+	// Quality gates
+	docCoverage := mod.Code.Docs.Coverage()
+	if docCoverage < cfg.minDocCoverage {
+		fatal(logger, fmt.Errorf("doc coverage %.1f%% is below minimum %.1f%%", docCoverage, cfg.minDocCoverage))
+	}
+	if cfg.maxFunctionLines > 0 {
+		longFunctions := mod.LongFunctions(cfg.maxFunctionLines)
+		for _, fn := range longFunctions {
+			logger.Warn("function too long", "function", fn.Name, "file", path.Join(fn.Package, fn.File), "line", fn.Line, "lines", fn.LineCount)
+		}
+		if len(longFunctions) > 0 {
+			fatal(logger, fmt.Errorf("%d functions have more than %d lines", len(longFunctions), cfg.maxFunctionLines))
+		}
+	}
+	if len(cfg.allowedLicenses) > 0 {
+		violations := mod.LicenseViolations(cfg.allowedLicenses)
+		for _, violation := range violations {
+			logger.Warn("license policy violation", "module", violation.Module, "reason", violation.Reason)
+		}
+		if len(violations) > 0 {
+			fatal(logger, fmt.Errorf("%d external dependencies violate the license policy (allowed: %s)", len(violations), strings.Join(cfg.allowedLicenses, ", ")))
+		}
+	}
+
+	if cfg.format == "text" {
+		return
+	}
+	if cfg.noOpen || cfg.format != "html" {
+		fmt.Println(strings.Join(outputPaths, "\n"))
+		return
+	}
+	err = io.OpenFile(outputPaths[0])
 	if err != nil {
-		log.Fatal(err)
+		fatal(logger, err)
 	}
 }
 
-// Get module path and output path from command-line args
-func getArgs() (modulePath string) {
-	args := os.Args[1:]
+// Command-line options
+type config struct {
+	workers   int
+	timeout   time.Duration
+	verbose   bool
+	logFormat string
+	counts    map[string]string // metric name => pattern
+	noOpen    bool
+	// Report format: html, markdown, text, or csv
+	format   string
+	sections []needle.MarkdownSection
+	html     needle.HTMLOptions // template directory and theme
+	// Cover profile from go test -coverprofile
+	coverProfile string
+	// Call graph export format: dot or json
+	callGraph string
+	// Analyze direct external dependencies from the module cache
+	external bool
+	// Quality gates
+	minDocCoverage   float64
+	maxFunctionLines int
+	allowedLicenses  []string // SPDX identifiers
+}
+
+// Get module path and command-line options from command-line args
+func getArgs() (modulePath string, cfg *config) {
+	cfg = &config{counts: make(map[string]string)}
+	flag.IntVar(&cfg.workers, "workers", runtime.GOMAXPROCS(0), "max number of files analyzed at a time")
+	flag.DurationVar(&cfg.timeout, "timeout", 0, "max analysis duration, e.g. 30s (0 = no timeout)")
+	flag.BoolVar(&cfg.verbose, "verbose", false, "show debug logs (stage durations, analyzed packages)")
+	flag.StringVar(&cfg.logFormat, "log-format", "text", "log format: text or json")
+	flag.BoolVar(&cfg.noOpen, "no-open", false, "print report path instead of opening it")
+	flag.StringVar(&cfg.format, "format", "html", "report format: html, markdown, text (printed), or csv (one file per table)")
+	flag.Func("sections", "markdown report sections, comma-separated: summary, packages, code, levels, graph (default all)", func(value string) error {
+		for _, section := range strings.Split(value, ",") {
+			section := needle.MarkdownSection(strings.TrimSpace(section))
+			if !slices.Contains(needle.MarkdownSections, section) {
+				return fmt.Errorf("unknown section %q", section)
+			}
+			cfg.sections = append(cfg.sections, section)
+		}
+		return nil
+	})
+	flag.StringVar(&cfg.html.TemplateDir, "template", "", "directory of HTML report templates that override the built-in ones")
+	flag.Func("theme", "HTML report theme: light, dark, or auto (default light)", func(value string) error {
+		theme := needle.Theme(value)
+		if !slices.Contains(needle.Themes, theme) {
+			return fmt.Errorf("unknown theme %q", theme)
+		}
+		cfg.html.Theme = theme
+		return nil
+	})
+	flag.StringVar(&cfg.coverProfile, "coverprofile", "", "Go cover profile (go test -coverprofile) to attribute statement coverage")
+	flag.BoolVar(&cfg.external, "external", false, "also analyze direct external dependencies from the module cache, with nested HTML reports")
+	flag.StringVar(&cfg.callGraph, "call-graph", "", "also export call graph: dot or json")
+	flag.Float64Var(&cfg.minDocCoverage, "min-doc-coverage", 0, "fail if doc coverage of exported identifiers (%) is below this")
+	flag.IntVar(&cfg.maxFunctionLines, "max-function-lines", 0, "fail if any function has more lines than this (0 = no limit)")
+	flag.Func("allowed-licenses", "fail if a direct external dependency has no allowed license, as comma-separated SPDX identifiers", func(value string) error {
+		for _, spdx := range strings.Split(value, ",") {
+			if spdx = strings.TrimSpace(spdx); spdx != "" {
+				cfg.allowedLicenses = append(cfg.allowedLicenses, spdx)
+			}
+		}
+		return nil
+	})
+	flag.Func("count", "count code lines matching pattern, as name=regexp (repeatable)", func(value string) error {
+		name, pattern, ok := strings.Cut(value, "=")
+		if !ok || name == "" || pattern == "" {
+			return fmt.Errorf("expected name=regexp, got %q", value)
+		}
+		cfg.counts[name] = pattern
+		return nil
+	})
+	flag.Usage = func() {
+		fmt.Println("Usage: needle [options] <modulePath>")
+		fmt.Println("       needle impact [options] <modulePath> <package|file>...")
+		fmt.Println("       needle why [options] <modulePath> <fromPackage> <toPackage|externalImportPath>")
+		fmt.Println("       needle vuln [options] --db <osvDir> <modulePath>")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	args := flag.Args()
 	numArgs := len(args)
 	if numArgs < 1 {
-		fmt.Println("Usage: needle <modulePath>")
+		flag.Usage()
+		os.Exit(1)
+	}
+	if cfg.logFormat != "text" && cfg.logFormat != "json" {
+		fmt.Printf("Invalid log format: %q\n", cfg.logFormat)
+		os.Exit(1)
+	}
+	if !slices.Contains([]string{"html", "markdown", "text", "csv"}, cfg.format) {
+		fmt.Printf("Invalid report format: %q\n", cfg.format)
+		os.Exit(1)
+	}
+	if cfg.callGraph != "" && cfg.callGraph != "dot" && cfg.callGraph != "json" {
+		fmt.Printf("Invalid call graph format: %q\n", cfg.callGraph)
 		os.Exit(1)
 	}
 	modulePath = args[0]
-	return modulePath
+	return modulePath, cfg
+}
+
+// Build and save module report in the chosen format, return the absolute output paths:
+// one per CSV table, or a single report
+func saveReport(mod *needle.Module, cfg *config) ([]string, error) {
+	reports := make(map[string]string) // ext => report
+	var err error
+	switch cfg.format {
+	case "markdown":
+		reports["md"] = needle.BuildMarkdownReport(mod, needle.MarkdownOptions{Sections: cfg.sections})
+	case "csv":
+		for _, table := range needle.CSVTables {
+			reports[string(table)+".csv"], err = needle.BuildCSVReport(mod, table)
+			if err != nil {
+				return nil, err
+			}
+		}
+	default:
+		reports["html"], err = needle.BuildHTMLReport(mod, cfg.html)
+		if err != nil {
+			return nil, err
+		}
+	}
+	outputPaths := make([]string, 0, len(reports))
+	for _, ext := range slices.Sorted(maps.Keys(reports)) {
+		outputPath, err := needle.SaveReport(mod, reports[ext], ext)
+		if err != nil {
+			return nil, err
+		}
+		outputPath, _ = filepath.Abs(outputPath)
+		outputPaths = append(outputPaths, outputPath)
+	}
+	return outputPaths, nil
+}
+
+// Save module call graph next to the report, in dot or json format
+func saveCallGraph(mod *needle.Module, format string) (string, error) {
+	var out strings.Builder
+	var err error
+	if format == "dot" {
+		err = mod.CallGraph.WriteDOT(&out)
+	} else {
+		err = mod.CallGraph.WriteJSON(&out)
+	}
+	if err != nil {
+		return "", err
+	}
+	outputPath, err := needle.SaveReport(mod, out.String(), "calls."+format)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Abs(outputPath)
+}
+
+// Create stderr logger: text or json format, debug level if verbose
+func newLogger(cfg *config) *slog.Logger {
+	level := slog.LevelWarn
+	if cfg.verbose {
+		level = slog.LevelDebug
+	}
+	handlerOpts := &slog.HandlerOptions{Level: level}
+	if cfg.logFormat == "json" {
+		return slog.New(slog.NewJSONHandler(os.Stderr, handlerOpts))
+	}
+	return slog.New(slog.NewTextHandler(os.Stderr, handlerOpts))
+}
+
+// Log error and exit
+func fatal(logger *slog.Logger, err error) {
+	logger.Error(err.Error())
+	os.Exit(1)
 }
@@ -0,0 +1,196 @@
+package needle
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/roidaradal/fn/dict"
+)
+
+// Custom metric counts: metric name => count
+type Metrics = dict.Counter[string]
+
+// Custom per-file, per-package, and per-module metrics analyzer.
+// FileDone and PackageDone may be called concurrently for different files and packages.
+type Analyzer interface {
+	// Unique analyzer name: letters, digits, dash, underscore
+	Name() string
+	// Called after a file is analyzed, with the file's raw lines.
+	// Returned metrics are stored on File.Extra and added to Package.Extra
+	FileDone(file *File, rawLines []string) Metrics
+	// Called after all files of a package are analyzed.
+	// Returned metrics are added to Package.Extra
+	PackageDone(pkg *Package) Metrics
+	// Called after all packages are analyzed, with Module.Extra already totaled
+	ModuleDone(mod *Module) error
+}
+
+// No-op Analyzer hooks, embed to implement only the hooks you need
+type BaseAnalyzer struct{}
+
+// No-op FileDone hook
+func (BaseAnalyzer) FileDone(*File, []string) Metrics {
+	return nil
+}
+
+// No-op PackageDone hook
+func (BaseAnalyzer) PackageDone(*Package) Metrics {
+	return nil
+}
+
+// No-op ModuleDone hook
+func (BaseAnalyzer) ModuleDone(*Module) error {
+	return nil
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Analyzer)
+	validName  = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+)
+
+// Register analyzer to be used in every Analyze call
+func Register(analyzer Analyzer) error {
+	name := analyzer.Name()
+	if !validName.MatchString(name) {
+		return fmt.Errorf("invalid analyzer name %q", name)
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if dict.HasKey(registry, name) {
+		return fmt.Errorf("analyzer %q already registered", name)
+	}
+	registry[name] = analyzer
+	return nil
+}
+
+// Return registered analyzers, sorted by name
+func registeredAnalyzers() []Analyzer {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := slices.Sorted(maps.Keys(registry))
+	analyzers := make([]Analyzer, len(names))
+	for i, name := range names {
+		analyzers[i] = registry[name]
+	}
+	return analyzers
+}
+
+// Combine registered analyzers and option analyzers, check for invalid and duplicate names
+func collectAnalyzers(mod *Module) error {
+	analyzers := registeredAnalyzers()
+	analyzers = append(analyzers, mod.options.Analyzers...)
+	names := make(map[string]bool)
+	for _, analyzer := range analyzers {
+		name := analyzer.Name()
+		if !validName.MatchString(name) {
+			return fmt.Errorf("invalid analyzer name %q", name)
+		}
+		if names[name] {
+			return fmt.Errorf("duplicate analyzer %q", name)
+		}
+		names[name] = true
+	}
+	mod.analyzers = analyzers
+	return nil
+}
+
+// Run FileDone hooks of module analyzers
+func (f *File) runAnalyzers(mod *Module, rawLines []string) {
+	for _, analyzer := range mod.analyzers {
+		metrics := analyzer.FileDone(f, rawLines)
+		if len(metrics) > 0 {
+			f.Extra[analyzer.Name()] = metrics
+		}
+	}
+}
+
+// Run PackageDone hooks of module analyzers
+func (pkg *Package) runAnalyzers(mod *Module) {
+	for _, analyzer := range mod.analyzers {
+		metrics := analyzer.PackageDone(pkg)
+		if len(metrics) > 0 {
+			addMetrics(pkg.Extra, analyzer.Name(), metrics)
+		}
+	}
+}
+
+// Run ModuleDone hooks of module analyzers
+func runModuleAnalyzers(ctx context.Context, mod *Module) error {
+	for _, analyzer := range mod.analyzers {
+		err := analyzer.ModuleDone(mod)
+		if err != nil {
+			return fmt.Errorf("analyzer %q: %w", analyzer.Name(), err)
+		}
+	}
+	return nil
+}
+
+// Add metrics to extra[name]
+func addMetrics(extra map[string]Metrics, name string, metrics Metrics) {
+	if dict.NoKey(extra, name) {
+		extra[name] = make(Metrics)
+	}
+	dict.UpdateCounts(extra[name], metrics)
+}
+
+// Add all metrics of source to extra
+func mergeMetrics(extra map[string]Metrics, source map[string]Metrics) {
+	for name, metrics := range source {
+		addMetrics(extra, name, metrics)
+	}
+}
+
+// Analyzer that counts code lines matching patterns
+type patternAnalyzer struct {
+	BaseAnalyzer
+	name     string
+	patterns map[string]*regexp.Regexp
+}
+
+// Create Analyzer that counts code lines (comments excluded) matching each pattern,
+// patterns: metric name => regular expression
+func NewPatternAnalyzer(name string, patterns map[string]string) (Analyzer, error) {
+	analyzer := &patternAnalyzer{
+		name:     name,
+		patterns: make(map[string]*regexp.Regexp),
+	}
+	for metric, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("metric %q: %w", metric, err)
+		}
+		analyzer.patterns[metric] = re
+	}
+	return analyzer, nil
+}
+
+// Pattern analyzer name
+func (a patternAnalyzer) Name() string {
+	return a.name
+}
+
+// Count matching lines in file, skipping comment lines
+func (a patternAnalyzer) FileDone(file *File, rawLines []string) Metrics {
+	metrics := make(Metrics)
+	for metric := range a.patterns {
+		metrics[metric] = 0
+	}
+	for i, rawLine := range rawLines {
+		if file.Lines[i].Type == LINE_COMMENT {
+			continue
+		}
+		rawLine = strings.TrimSpace(rawLine)
+		for metric, re := range a.patterns {
+			if re.MatchString(rawLine) {
+				metrics[metric] += 1
+			}
+		}
+	}
+	return metrics
+}
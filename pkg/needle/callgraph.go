@@ -0,0 +1,247 @@
+package needle
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/roidaradal/fn/ds"
+)
+
+// Module-internal static call graph of code files.
+// Function IDs are package.Name or package.Type.Method (without package for the root package)
+type CallGraph struct {
+	Calls []*Call `json:"calls"` // caller => callee edges, sorted by caller, callee
+}
+
+// Call graph edge
+type Call struct {
+	Caller        string `json:"caller"`        // caller function ID
+	CallerPackage string `json:"callerPackage"` // caller package name
+	Callee        string `json:"callee"`        // callee function ID
+	CalleePackage string `json:"calleePackage"` // callee package name
+	Sites         int    `json:"sites"`         // number of call sites
+	Dynamic       bool   `json:"dynamic"`       // interface method call, resolved to an implementation
+}
+
+// Cross-package call summary
+type PackageCalls struct {
+	From      string `json:"from"`      // caller package name
+	To        string `json:"to"`        // callee package name
+	Sites     int    `json:"sites"`     // number of call sites
+	Functions int    `json:"functions"` // distinct callee functions
+}
+
+// Build the module call graph from the type-checked code files:
+// resolve static calls and interface method calls to module functions
+func computeCallGraph(ctx context.Context, mod *Module) error {
+	mod.CallGraph = CallGraph{Calls: make([]*Call, 0)}
+	checker := mod.checker
+	functions := make(map[string]*Function) // function ID => Function
+	for _, pkg := range mod.Packages {
+		for _, fn := range pkg.Functions {
+			functions[symbolID(pkg.Name, fn.Name)] = fn
+		}
+	}
+	namedTypes := make(map[string]*NamedType) // type ID => NamedType
+	for _, t := range mod.TypeGraph.Types {
+		namedTypes[t.ID] = t
+	}
+
+	calls := make(map[[2]string]*Call)
+	addCall := func(caller, callee string, dynamic bool) {
+		fn, ok := functions[callee]
+		if !ok {
+			return
+		}
+		key := [2]string{caller, callee}
+		if calls[key] == nil {
+			calls[key] = &Call{
+				Caller:        caller,
+				CallerPackage: functions[caller].Package,
+				Callee:        callee,
+				CalleePackage: fn.Package,
+				Dynamic:       dynamic,
+			}
+		}
+		calls[key].Sites += 1
+	}
+	for _, pkg := range mod.Packages {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		tpkg, info := checker.check(pkg)
+		if tpkg == nil {
+			continue
+		}
+		for _, f := range pkg.Files {
+			if f.Type != FILE_CODE || f.syntax == nil {
+				continue
+			}
+			for _, decl := range f.syntax.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || fn.Body == nil {
+					continue
+				}
+				name := fn.Name.Name
+				if fn.Recv != nil {
+					name = receiverTypeName(fn) + "." + name
+				}
+				caller := symbolID(pkg.Name, name)
+				if _, ok := functions[caller]; !ok {
+					continue
+				}
+				ast.Inspect(fn.Body, func(node ast.Node) bool {
+					call, ok := node.(*ast.CallExpr)
+					if !ok {
+						return true
+					}
+					obj := calleeObject(info, call.Fun)
+					if obj == nil {
+						return true
+					}
+					recv := obj.Type().(*types.Signature).Recv()
+					if recv == nil || !types.IsInterface(recv.Type()) {
+						addCall(caller, checker.funcID(obj), false)
+						return true
+					}
+					// Interface method call: all implementations of the module interface,
+					// counted once per call site (T and *T, promoted methods may resolve to the same method)
+					iface, ok := namedTypes[checker.typeID(recv.Type())]
+					if !ok || !iface.IsInterface() {
+						return true
+					}
+					callees := ds.NewSet[string]()
+					for _, impl := range iface.Implementations {
+						named := checker.named(namedTypes[strings.TrimPrefix(impl, "*")])
+						if named == nil {
+							continue
+						}
+						method, _, _ := types.LookupFieldOrMethod(types.NewPointer(named), true, obj.Pkg(), obj.Name())
+						if method, ok := method.(*types.Func); ok {
+							callees.Add(checker.funcID(method))
+						}
+					}
+					for _, callee := range callees.Items() {
+						addCall(caller, callee, true)
+					}
+					return true
+				})
+			}
+		}
+	}
+
+	for _, call := range calls {
+		mod.CallGraph.Calls = append(mod.CallGraph.Calls, call)
+		functions[call.Caller].Callees = append(functions[call.Caller].Callees, call.Callee)
+		functions[call.Callee].Callers = append(functions[call.Callee].Callers, call.Caller)
+	}
+	for _, fn := range functions {
+		slices.Sort(fn.Callers)
+		slices.Sort(fn.Callees)
+	}
+	slices.SortFunc(mod.CallGraph.Calls, func(a, b *Call) int {
+		return cmp.Or(cmp.Compare(a.Caller, b.Caller), cmp.Compare(a.Callee, b.Callee))
+	})
+	return nil
+}
+
+// Get called function or method object, nil if not a function call (e.g. conversion, builtin, func value)
+func calleeObject(info *types.Info, fun ast.Expr) *types.Func {
+	var ident *ast.Ident
+	switch e := ast.Unparen(fun).(type) {
+	case *ast.Ident:
+		ident = e
+	case *ast.SelectorExpr:
+		ident = e.Sel
+	case *ast.IndexExpr: // generic function instantiation
+		return calleeObject(info, e.X)
+	case *ast.IndexListExpr:
+		return calleeObject(info, e.X)
+	default:
+		return nil
+	}
+	obj, ok := info.Uses[ident].(*types.Func)
+	if !ok {
+		return nil
+	}
+	return obj.Origin()
+}
+
+// Cross-package call counts, sorted by descending call sites
+func (g CallGraph) PackageCalls() []*PackageCalls {
+	type key struct{ from, to string }
+	summary := make(map[key]*PackageCalls)
+	callees := make(map[key]*ds.Set[string])
+	for _, call := range g.Calls {
+		from, to := call.CallerPackage, call.CalleePackage
+		if from == to {
+			continue
+		}
+		k := key{from, to}
+		if summary[k] == nil {
+			summary[k] = &PackageCalls{From: from, To: to}
+			callees[k] = ds.NewSet[string]()
+		}
+		summary[k].Sites += call.Sites
+		callees[k].Add(call.Callee)
+	}
+	out := make([]*PackageCalls, 0, len(summary))
+	for k, calls := range summary {
+		calls.Functions = callees[k].Len()
+		out = append(out, calls)
+	}
+	slices.SortFunc(out, func(a, b *PackageCalls) int {
+		return cmp.Or(cmp.Compare(b.Sites, a.Sites), cmp.Compare(a.From, b.From), cmp.Compare(a.To, b.To))
+	})
+	return out
+}
+
+// Write call graph in Graphviz DOT format, one cluster per package
+func (g CallGraph) WriteDOT(w io.Writer) error {
+	clusters := make(map[string]*ds.Set[string]) // package => function IDs
+	addNode := func(pkg, id string) {
+		if clusters[pkg] == nil {
+			clusters[pkg] = ds.NewSet[string]()
+		}
+		clusters[pkg].Add(id)
+	}
+	for _, call := range g.Calls {
+		addNode(call.CallerPackage, call.Caller)
+		addNode(call.CalleePackage, call.Callee)
+	}
+	lines := []string{"digraph calls {", "    rankdir=LR;", "    node [shape=box];"}
+	for i, pkg := range slices.Sorted(maps.Keys(clusters)) {
+		lines = append(lines, fmt.Sprintf("    subgraph cluster_%d {", i), fmt.Sprintf("        label=%q;", pkg))
+		ids := clusters[pkg].Items()
+		slices.Sort(ids)
+		for _, id := range ids {
+			lines = append(lines, fmt.Sprintf("        %q;", id))
+		}
+		lines = append(lines, "    }")
+	}
+	for _, call := range g.Calls {
+		attrs := fmt.Sprintf("label=%d", call.Sites)
+		if call.Dynamic {
+			attrs += ", style=dashed"
+		}
+		lines = append(lines, fmt.Sprintf("    %q -> %q [%s];", call.Caller, call.Callee, attrs))
+	}
+	lines = append(lines, "}", "")
+	_, err := io.WriteString(w, strings.Join(lines, "\n"))
+	return err
+}
+
+// Write call graph as indented JSON
+func (g CallGraph) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(g)
+}
@@ -0,0 +1,129 @@
+package needle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCallGraphWriteDOT(t *testing.T) {
+	tests := []struct {
+		name  string
+		graph CallGraph
+		want  []string
+	}{
+		{"empty", CallGraph{Calls: []*Call{}}, []string{
+			"digraph calls {",
+			"    rankdir=LR;",
+			"    node [shape=box];",
+			"}",
+		}},
+		{"clusters and edges", CallGraph{Calls: []*Call{
+			{Caller: "main", CallerPackage: "/", Callee: "store.Load", CalleePackage: "store", Sites: 2},
+			{Caller: "main", CallerPackage: "/", Callee: "store.Saver.Save", CalleePackage: "store", Sites: 1, Dynamic: true},
+			{Caller: "store.Load", CallerPackage: "store", Callee: "store.read", CalleePackage: "store", Sites: 1},
+		}}, []string{
+			"digraph calls {",
+			"    rankdir=LR;",
+			"    node [shape=box];",
+			"    subgraph cluster_0 {",
+			`        label="/";`,
+			`        "main";`,
+			"    }",
+			"    subgraph cluster_1 {",
+			`        label="store";`,
+			`        "store.Load";`,
+			`        "store.Saver.Save";`,
+			`        "store.read";`,
+			"    }",
+			`    "main" -> "store.Load" [label=2];`,
+			`    "main" -> "store.Saver.Save" [label=1, style=dashed];`,
+			`    "store.Load" -> "store.read" [label=1];`,
+			"}",
+		}},
+		{"quoted IDs", CallGraph{Calls: []*Call{
+			{Caller: `a"b`, CallerPackage: `p"q`, Callee: `a"b`, CalleePackage: `p"q`, Sites: 1},
+		}}, []string{
+			"digraph calls {",
+			"    rankdir=LR;",
+			"    node [shape=box];",
+			"    subgraph cluster_0 {",
+			`        label="p\"q";`,
+			`        "a\"b";`,
+			"    }",
+			`    "a\"b" -> "a\"b" [label=1];`,
+			"}",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.graph.WriteDOT(&buf); err != nil {
+				t.Fatal(err)
+			}
+			want := strings.Join(tt.want, "\n") + "\n"
+			if got := buf.String(); got != want {
+				t.Errorf("WriteDOT() =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestCallGraphWriteJSON(t *testing.T) {
+	graph := CallGraph{Calls: []*Call{
+		{Caller: "main", CallerPackage: "/", Callee: "store.Load", CalleePackage: "store", Sites: 2, Dynamic: true},
+	}}
+	var buf bytes.Buffer
+	if err := graph.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "calls": [
+    {
+      "caller": "main",
+      "callerPackage": "/",
+      "callee": "store.Load",
+      "calleePackage": "store",
+      "sites": 2,
+      "dynamic": true
+    }
+  ]
+}
+`
+	if got := buf.String(); got != want {
+		t.Errorf("WriteJSON() =\n%s\nwant\n%s", got, want)
+	}
+	var decoded CallGraph
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Calls) != 1 || *decoded.Calls[0] != *graph.Calls[0] {
+		t.Errorf("WriteJSON() round trip = %v, want %v", decoded.Calls, graph.Calls)
+	}
+}
+
+func TestComputeCallGraph(t *testing.T) {
+	mod, err := Analyze(context.Background(), "testdata/modules/errors", WithoutModuleGraph())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make([]string, 0)
+	for _, call := range mod.CallGraph.Calls {
+		got = append(got, fmt.Sprintf("%s -> %s (%d)", call.Caller, call.Callee, call.Sites))
+	}
+	want := []string{
+		"main -> pair (1)",
+		"main -> run (3)",
+		"main -> store.Cache.Save (1)",
+		"main -> store.New (1)",
+		"main -> store.Store.Load (1)",
+		"main -> store.Store.Save (2)", // call and deferred call, not the method value
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Calls = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,210 @@
+package needle
+
+import (
+	"cmp"
+	"fmt"
+	"go/build"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/roidaradal/fn/ds"
+	"github.com/roidaradal/fn/io"
+)
+
+// Import chain query: why does From depend on To
+type ChainQuery struct {
+	From  string // internal package: package name, node name (/a/b) or import path
+	To    string // internal package, or external import path (prefix of imported packages, e.g. module path)
+	All   bool   // all import chains, instead of one shortest chain
+	Tests bool   // also follow imports of test files
+	Limit int    // if All, stop after finding Limit chains (0 = no limit)
+}
+
+// Import chain: sequence of import hops from a package to its (transitive) dependency
+type ImportChain []*ImportHop
+
+// Import chain hop: From imports To (first importing file, by file name)
+type ImportHop struct {
+	From       string `json:"from"`       // importing package name
+	To         string `json:"to"`         // imported package name, or import path if external
+	ImportPath string `json:"importPath"` // imported package import path
+	File       string `json:"file"`       // importing file: package/file.go
+	Line       int    `json:"line"`       // line of import spec
+}
+
+// Find import chains from one internal package to another, or to an external package:
+// one shortest chain, or all chains sorted by length
+func (mod Module) ImportChains(q ChainQuery) ([]ImportChain, error) {
+	isPackage := ds.NewSet[string]()
+	for _, pkg := range mod.Packages {
+		isPackage.Add(pkg.Name)
+	}
+	from := mod.packageName(q.From)
+	if !isPackage.Has(from) {
+		return nil, fmt.Errorf("package %q not found in module %q", q.From, mod.Name)
+	}
+	to := mod.packageName(q.To)
+	isExternal := !isPackage.Has(to)
+	if isExternal {
+		to = strings.TrimSuffix(q.To, "/")
+		// Internal path (module path, or no domain and not standard library): typo, not an external package
+		isModulePath := to == mod.Name || startsWith(to, mod.Name+"/")
+		isDomain := strings.Contains(strings.Split(to, "/")[0], ".")
+		if isModulePath || (!isDomain && !isStandardPackage(to)) {
+			return nil, fmt.Errorf("package %q not found in module %q", q.To, mod.Name)
+		}
+	}
+	isTarget := func(hop *ImportHop) bool {
+		if isExternal {
+			return hop.ImportPath == to || startsWith(hop.ImportPath, to+"/")
+		}
+		return hop.To == to
+	}
+
+	hops := mod.importHops(q.Tests)
+	chains := make([]ImportChain, 0)
+	if !q.All {
+		// Breadth-first search: first chain found is a shortest one
+		parent := map[string]*ImportHop{from: nil}
+		queue := ds.QueueFrom([]string{from})
+		for queue.NotEmpty() {
+			pkgName, _ := queue.Dequeue()
+			for _, hop := range hops[pkgName] {
+				if isTarget(hop) {
+					chain := ImportChain{hop}
+					for prev := parent[pkgName]; prev != nil; prev = parent[prev.From] {
+						chain = append(chain, prev)
+					}
+					slices.Reverse(chain)
+					return append(chains, chain), nil
+				}
+				if _, seen := parent[hop.To]; !seen && isPackage.Has(hop.To) {
+					parent[hop.To] = hop
+					queue.Enqueue(hop.To)
+				}
+			}
+		}
+		return chains, nil
+	}
+
+	// Packages that reach the target: only these are explored
+	users := make(map[string][]string) // package => internal packages that import it
+	reaches := ds.NewSet[string]()
+	for pkgName, pkgHops := range hops {
+		for _, hop := range pkgHops {
+			users[hop.To] = append(users[hop.To], pkgName)
+			if isTarget(hop) {
+				reaches.Add(pkgName)
+			}
+		}
+	}
+	queue := ds.QueueFrom(reaches.Items())
+	for queue.NotEmpty() {
+		pkgName, _ := queue.Dequeue()
+		for _, user := range users[pkgName] {
+			if !reaches.Has(user) {
+				reaches.Add(user)
+				queue.Enqueue(user)
+			}
+		}
+	}
+
+	// Depth-first search: all simple chains (test imports may form cycles)
+	visiting := ds.NewSet[string]()
+	var current ImportChain
+	var visit func(pkgName string)
+	visit = func(pkgName string) {
+		visiting.Add(pkgName)
+		for _, hop := range hops[pkgName] {
+			if q.Limit > 0 && len(chains) >= q.Limit {
+				break
+			}
+			if isTarget(hop) {
+				chains = append(chains, slices.Clone(append(current, hop)))
+			} else if reaches.Has(hop.To) && !visiting.Has(hop.To) {
+				current = append(current, hop)
+				visit(hop.To)
+				current = current[:len(current)-1]
+			}
+		}
+		visiting.Delete(pkgName)
+	}
+	visit(from)
+	slices.SortStableFunc(chains, func(a, b ImportChain) int {
+		return cmp.Compare(len(a), len(b))
+	})
+	return chains, nil
+}
+
+// Get import hops of each package, from its code files (and test files),
+// sorted by imported package; one hop per imported package, from the first importing file
+func (mod Module) importHops(withTests bool) map[string][]*ImportHop {
+	hops := make(map[string][]*ImportHop)
+	for _, pkg := range mod.Packages {
+		imported := make(map[string]*ImportHop)
+		for _, f := range pkg.Files {
+			if f.syntax == nil || (f.Type == FILE_TEST && !withTests) {
+				continue
+			}
+			for _, spec := range f.syntax.Imports {
+				importPath, err := strconv.Unquote(spec.Path.Value)
+				if err != nil || importPath == "C" {
+					continue
+				}
+				to := importPath
+				if importPath == mod.Name || startsWith(importPath, mod.Name+"/") {
+					to = mod.packageName(importPath)
+				}
+				if to == pkg.Name {
+					continue // external test package importing its own package
+				}
+				hop := &ImportHop{
+					From:       pkg.Name,
+					To:         to,
+					ImportPath: importPath,
+					File:       packageFilePath(pkg.Name, f.Name),
+					Line:       mod.fset.Position(spec.Pos()).Line,
+				}
+				if prev, ok := imported[to]; !ok || hop.File < prev.File {
+					imported[to] = hop
+				}
+			}
+		}
+		for _, hop := range imported {
+			hops[pkg.Name] = append(hops[pkg.Name], hop)
+		}
+		slices.SortFunc(hops[pkg.Name], func(a, b *ImportHop) int {
+			return cmp.Compare(a.To, b.To)
+		})
+	}
+	return hops
+}
+
+// Package names of import chain: From of each hop, and last To
+func (c ImportChain) Packages() []string {
+	names := make([]string, 0, len(c)+1)
+	for _, hop := range c {
+		names = append(names, hop.From)
+	}
+	if len(c) > 0 {
+		names = append(names, c[len(c)-1].To)
+	}
+	return names
+}
+
+// Highlight import chains in the report's dependency graph
+func (mod *Module) Highlight(chains []ImportChain) {
+	mod.highlight = make([][2]string, 0)
+	for _, chain := range chains {
+		for _, hop := range chain {
+			mod.highlight = append(mod.highlight, [2]string{hop.From, hop.To})
+		}
+	}
+}
+
+// Check if import path is a standard library package (folder in GOROOT/src)
+func isStandardPackage(importPath string) bool {
+	return build.Default.GOROOT != "" && io.IsDir(filepath.Join(build.Default.GOROOT, "src", filepath.FromSlash(importPath)))
+}
@@ -1,12 +1,14 @@
 package needle
 
 import (
+	"context"
 	"fmt"
+	"go/parser"
 	"maps"
 	"path/filepath"
+	"slices"
 	"strings"
 
-	"github.com/roidaradal/fn/conk"
 	"github.com/roidaradal/fn/dict"
 	"github.com/roidaradal/fn/io"
 	"github.com/roidaradal/fn/lang"
@@ -14,23 +16,31 @@ import (
 )
 
 // Build the module tree, computing the dependencies, stats, and code composition
-func buildModuleTree(mod *Module) error {
+func buildModuleTree(ctx context.Context, mod *Module) error {
 	type data struct {
 		name string
 		pkg  *Package
 	}
 
 	// Run concurrently
-	task := func(entry NodeEntry) (data, error) {
+	task := func(ctx context.Context, entry NodeEntry) (data, error) {
 		var d data
 		name, node := entry.Tuple()
-		pkg, err := newPackage(mod, name, node.Files)
+		pkg, err := newPackage(ctx, mod, name, node.Files)
 		if err != nil {
 			return d, err
 		}
 		return data{name, pkg}, nil
 	}
 	onReceive := func(d data) {
+		mod.progress.update(func(e *ProgressEvent) {
+			e.Packages += 1
+		})
+		mod.options.Logger.Debug("package analyzed",
+			"package", d.pkg.Name,
+			"files", d.pkg.FileCount(),
+			"lines", d.pkg.LineCount,
+		)
 		mod.Packages = append(mod.Packages, d.pkg)
 		mod.LineCount += d.pkg.LineCount
 		mod.CharCount += d.pkg.CharCount
@@ -41,7 +51,14 @@ func buildModuleTree(mod *Module) error {
 		dict.UpdateCounts(mod.Code.Chars, d.pkg.CharTypes)
 		dict.UpdateCounts(mod.Code.Blocks, d.pkg.Blocks)
 		dict.UpdateCounts(mod.Code.Types, d.pkg.Codes)
+		mergeMetrics(mod.Extra, d.pkg.Extra)
+		mod.Code.Errors.add(d.pkg.Errors)
+		mod.Code.Docs.add(d.pkg.Docs)
+		mod.Code.Tests.add(d.pkg.Tests)
 		for dep, isInternal := range d.pkg.Deps {
+			if dep == d.name {
+				continue // external test package importing its own package
+			}
 			if isInternal {
 				mod.Deps.Of[d.name] = append(mod.Deps.Of[d.name], dep)
 			} else {
@@ -50,11 +67,15 @@ func buildModuleTree(mod *Module) error {
 		}
 	}
 	entries := mod.packageNodeEntries()
-	err := conk.Tasks(entries, task, onReceive)
+	err := runTasks(ctx, mod.options.Workers, entries, task, onReceive)
 	if err != nil {
 		return err
 	}
 
+	slices.SortFunc(mod.Code.Docs.Undocumented, compareSymbols)
+	slices.SortFunc(mod.Code.Tests.Tests, compareTestFuncs)
+	slices.SortFunc(mod.Code.Tests.Untested, compareSymbols)
+
 	// Compute inverse dependency => which package uses it
 	mod.Deps.InternalUsers = dict.GroupByValueList(mod.Deps.Of)
 	dict.SortValues(mod.Deps.InternalUsers)
@@ -69,7 +90,7 @@ func buildModuleTree(mod *Module) error {
 }
 
 // Build Package object for given name
-func newPackage(mod *Module, name string, files []string) (*Package, error) {
+func newPackage(ctx context.Context, mod *Module, name string, files []string) (*Package, error) {
 	folder := mod.Path + name
 	pkg := &Package{
 		Name:      nodeToPackageName(name),
@@ -82,26 +103,43 @@ func newPackage(mod *Module, name string, files []string) (*Package, error) {
 		FileChars: make(dict.Counter[FileType]),
 		LineTypes: make(dict.Counter[LineType]),
 		CharTypes: make(dict.Counter[LineType]),
+		Extra:     make(map[string]Metrics),
 	}
 
 	// Run concurrently
-	task := func(filename string) (*File, error) {
+	task := func(ctx context.Context, filename string) (*File, error) {
+		err := mod.acquireWorker(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer mod.releaseWorker()
 		path := filepath.Join(folder, filename)
-		return newFile(mod, pkg, path)
+		return newFile(mod, path)
 	}
 	onReceive := func(file *File) {
+		mod.progress.update(func(e *ProgressEvent) {
+			e.Files += 1
+		})
+		if pkg.Type == "" {
+			pkg.Type = file.pkgType
+		}
 		pkg.Files = append(pkg.Files, file)
 		maps.Copy(pkg.Deps, file.Deps)
 		dict.UpdateCounts(pkg.Blocks, file.Blocks)
 		dict.UpdateCounts(pkg.Codes, file.Codes)
 		dict.UpdateCounts(pkg.LineTypes, file.LineTypes)
 		dict.UpdateCounts(pkg.CharTypes, file.CharTypes)
+		mergeMetrics(pkg.Extra, file.Extra)
 	}
-	err := conk.Tasks(files, task, onReceive)
+	err := runTasks(ctx, mod.options.Workers, files, task, onReceive)
 	if err != nil {
 		return nil, err
 	}
 
+	// Package with only external test files
+	if pkg.Type == "" {
+		pkg.Type = PKG_LIB
+	}
 	// Set file type stats
 	pkg.FileTypes = dict.CounterFunc(pkg.Files, func(f *File) FileType {
 		return f.Type
@@ -115,6 +153,11 @@ func newPackage(mod *Module, name string, files []string) (*Package, error) {
 		pkg.FileChars[f.Type] += numChars
 		pkg.CharCount += numChars
 	}
+	pkg.computeErrorProfile()
+	pkg.computeDocProfile(mod)
+	pkg.computeFunctions(mod)
+	pkg.computeTestProfile(mod)
+	pkg.runAnalyzers(mod)
 	return pkg, nil
 }
 
@@ -130,13 +173,8 @@ const (
 	modeConstGroup
 )
 
-const (
-	errLine   string = "if err != nil {"
-	errSuffix string = "; if err != nil {"
-)
-
 // Build File object for given file path
-func newFile(mod *Module, pkg *Package, path string) (*File, error) {
+func newFile(mod *Module, path string) (*File, error) {
 	if !io.PathExists(path) {
 		return nil, fmt.Errorf("file %q does not exist", path)
 	}
@@ -150,6 +188,7 @@ func newFile(mod *Module, pkg *Package, path string) (*File, error) {
 		Codes:     make(dict.Counter[CodeType]),
 		LineTypes: make(dict.Counter[LineType]),
 		CharTypes: make(dict.Counter[LineType]),
+		Extra:     make(map[string]Metrics),
 	}
 
 	lines, err := io.ReadRawLines(path)
@@ -189,8 +228,9 @@ func newFile(mod *Module, pkg *Package, path string) (*File, error) {
 		} else if startsWith(cleanLine, "package ") {
 			// Package header
 			line = newHeadLine(rawCount)
-			if name, ok := getLinePart(cleanLine, 1); ok && pkg.Type == "" {
-				pkg.Type = lang.Ternary(name == "main", PKG_MAIN, PKG_LIB)
+			// External test package foo_test does not decide package type
+			if name, ok := getLinePart(cleanLine, 1); ok && !endsWith(name, "_test") {
+				file.pkgType = lang.Ternary(name == "main", PKG_MAIN, PKG_LIB)
 			}
 		} else if startsWith(cleanLine, "import ") {
 			// Import header
@@ -215,7 +255,7 @@ func newFile(mod *Module, pkg *Package, path string) (*File, error) {
 					file.addDependency(mod, dep)
 				}
 			}
-		} else if cleanLine == errLine || endsWith(cleanLine, errSuffix) {
+		} else if isErrorCheckLine(cleanLine) {
 			// Start error mode
 			line = newErrorLine(rawCount)
 			currMode = modeError
@@ -314,9 +354,27 @@ func newFile(mod *Module, pkg *Package, path string) (*File, error) {
 		file.CharTypes[line.Type] += line.Length
 	}
 
+	// Parse syntax tree for error profile
+	file.syntax, err = parser.ParseFile(mod.fset, path, strings.Join(lines, "\n"), parser.ParseComments)
+	if err != nil {
+		mod.options.Logger.Debug("syntax error", "file", path, "error", err)
+		file.syntax = nil
+	} else {
+		file.Errors = newErrorProfile(file.syntax)
+	}
+
+	file.runAnalyzers(mod, lines)
 	return file, nil
 }
 
+// Compute package error profile: add file error profiles
+// (unchecked calls and ignored errors are added after type-checking)
+func (pkg *Package) computeErrorProfile() {
+	for _, f := range pkg.Files {
+		pkg.Errors.add(f.Errors)
+	}
+}
+
 // Classify function as (public/private) x (function/method)
 func classifyFunction(line string) CodeType {
 	parts := str.SpaceSplit(line)
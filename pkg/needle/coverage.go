@@ -0,0 +1,192 @@
+package needle
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/roidaradal/fn/number"
+)
+
+// Statement coverage from a Go cover profile
+type Coverage struct {
+	Statements int `json:"statements"` // statements in profile blocks
+	Covered    int `json:"covered"`    // statements executed at least once
+}
+
+// Cover profile block: file.go:startLine.startCol,endLine.endCol numStmts count
+type coverBlock struct {
+	file      string // import path of file
+	startLine int
+	startCol  int
+	endLine   int
+	endCol    int
+	numStmts  int
+	count     int
+}
+
+// Add other coverage counts
+func (c *Coverage) add(other Coverage) {
+	c.Statements += other.Statements
+	c.Covered += other.Covered
+}
+
+// Percentage of covered statements (0 if no statements)
+func (c Coverage) Percent() float64 {
+	if c.Statements == 0 {
+		return 0
+	}
+	return number.Ratio(c.Covered*100, c.Statements)
+}
+
+// Check if module has coverage data from a cover profile
+func (mod Module) HasCoverage() bool {
+	return mod.options != nil && mod.options.CoverProfile != ""
+}
+
+// Read cover profile (if any) and attribute statement coverage
+// to the module's packages, files, and functions
+func applyCoverProfile(ctx context.Context, mod *Module) error {
+	if !mod.HasCoverage() {
+		return nil
+	}
+	blocks, err := readCoverProfile(mod.options.CoverProfile)
+	if err != nil {
+		return err
+	}
+
+	// File import path => File, Package
+	type fileEntry struct {
+		file *File
+		pkg  *Package
+	}
+	files := make(map[string]fileEntry)
+	for _, pkg := range mod.Packages {
+		for _, f := range pkg.Files {
+			files[path.Join(mod.ImportPath(pkg), f.Name)] = fileEntry{f, pkg}
+		}
+	}
+
+	unknown := 0
+	for _, block := range blocks {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		entry, ok := files[block.file]
+		if !ok {
+			unknown += 1
+			continue
+		}
+		coverage := Coverage{Statements: block.numStmts}
+		if block.count > 0 {
+			coverage.Covered = block.numStmts
+		}
+		entry.file.Coverage.add(coverage)
+		entry.pkg.Coverage.add(coverage)
+		mod.Code.Coverage.add(coverage)
+		for _, fn := range entry.pkg.Functions {
+			if fn.File == entry.file.Name && fn.Line <= block.startLine && block.startLine < fn.Line+fn.LineCount {
+				fn.Coverage.add(coverage)
+				break
+			}
+		}
+	}
+	if unknown > 0 {
+		mod.options.Logger.Warn("cover profile blocks outside module", "blocks", unknown, "module", mod.Name)
+	}
+	return nil
+}
+
+// Read Go cover profile: merge duplicate blocks (e.g. from multiple test binaries)
+// by keeping the max count, and return blocks in file order
+func readCoverProfile(profilePath string) ([]*coverBlock, error) {
+	file, err := os.Open(profilePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	blocks := make([]*coverBlock, 0)
+	index := make(map[string]*coverBlock) // block position => merged block
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber += 1
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if lineNumber == 1 {
+			if !startsWith(line, "mode: ") {
+				return nil, fmt.Errorf("cover profile %q: expected mode line, got %q", profilePath, line)
+			}
+			continue
+		}
+		block, err := parseCoverBlock(line)
+		if err != nil {
+			return nil, fmt.Errorf("cover profile %q line %d: %w", profilePath, lineNumber, err)
+		}
+		key, _, _ := strings.Cut(line, " ")
+		if prev, ok := index[key]; ok {
+			prev.count = max(prev.count, block.count)
+			continue
+		}
+		index[key] = block
+		blocks = append(blocks, block)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
+
+// Parse cover profile block line: file.go:startLine.startCol,endLine.endCol numStmts count
+func parseCoverBlock(line string) (*coverBlock, error) {
+	colon := strings.LastIndex(line, ":")
+	if colon < 0 {
+		return nil, fmt.Errorf("missing file separator in %q", line)
+	}
+	parts := strings.Fields(line[colon+1:])
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("expected position, statements, count in %q", line)
+	}
+	start, end, ok := strings.Cut(parts[0], ",")
+	if !ok {
+		return nil, fmt.Errorf("invalid block position %q", parts[0])
+	}
+	block := &coverBlock{file: line[:colon]}
+	var err error
+	block.startLine, block.startCol, err = parseCoverPosition(start)
+	if err != nil {
+		return nil, err
+	}
+	block.endLine, block.endCol, err = parseCoverPosition(end)
+	if err != nil {
+		return nil, err
+	}
+	block.numStmts, err = strconv.Atoi(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid statement count %q", parts[1])
+	}
+	block.count, err = strconv.Atoi(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid block count %q", parts[2])
+	}
+	return block, nil
+}
+
+// Parse cover profile position: line.col
+func parseCoverPosition(position string) (int, int, error) {
+	lineText, colText, ok := strings.Cut(position, ".")
+	line, err1 := strconv.Atoi(lineText)
+	col, err2 := strconv.Atoi(colText)
+	if !ok || cmp.Or(err1, err2) != nil {
+		return 0, 0, fmt.Errorf("invalid block position %q", position)
+	}
+	return line, col, nil
+}
@@ -0,0 +1,83 @@
+package needle
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestReadCoverProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile string
+		want    []coverBlock
+		wantErr bool
+	}{
+		{
+			name:    "single",
+			profile: "mode: set\nexample.com/m/a.go:3.14,5.2 2 1\n",
+			want:    []coverBlock{{"example.com/m/a.go", 3, 14, 5, 2, 2, 1}},
+		},
+		{
+			name: "merge by max count",
+			profile: "mode: count\n" +
+				"example.com/m/a.go:3.14,5.2 2 0\n" +
+				"example.com/m/b.go:7.1,8.2 1 4\n" +
+				"example.com/m/a.go:3.14,5.2 2 3\n" +
+				"example.com/m/b.go:7.1,8.2 1 2\n",
+			want: []coverBlock{
+				{"example.com/m/a.go", 3, 14, 5, 2, 2, 3},
+				{"example.com/m/b.go", 7, 1, 8, 2, 1, 4},
+			},
+		},
+		{
+			name:    "same start, different end",
+			profile: "mode: set\nexample.com/m/a.go:3.14,5.2 2 0\nexample.com/m/a.go:3.14,6.2 3 1\n",
+			want: []coverBlock{
+				{"example.com/m/a.go", 3, 14, 5, 2, 2, 0},
+				{"example.com/m/a.go", 3, 14, 6, 2, 3, 1},
+			},
+		},
+		{
+			name:    "blank lines",
+			profile: "mode: atomic\n\nexample.com/m/a.go:1.1,2.2 1 0\n\n",
+			want:    []coverBlock{{"example.com/m/a.go", 1, 1, 2, 2, 1, 0}},
+		},
+		{
+			name:    "windows path",
+			profile: "mode: set\nC:/m/a.go:1.1,2.2 1 1\n",
+			want:    []coverBlock{{"C:/m/a.go", 1, 1, 2, 2, 1, 1}},
+		},
+		{name: "missing mode", profile: "example.com/m/a.go:3.14,5.2 2 1\n", wantErr: true},
+		{name: "missing count", profile: "mode: set\nexample.com/m/a.go:3.14,5.2 2\n", wantErr: true},
+		{name: "invalid position", profile: "mode: set\nexample.com/m/a.go:3,5.2 2 1\n", wantErr: true},
+		{name: "invalid count", profile: "mode: set\nexample.com/m/a.go:3.14,5.2 2 x\n", wantErr: true},
+		{name: "missing file", profile: "mode: set\n3.14,5.2 2 1\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "cover.out")
+			if err := os.WriteFile(path, []byte(tt.profile), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			blocks, err := readCoverProfile(path)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("readCoverProfile() = %v, want error", blocks)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]coverBlock, len(blocks))
+			for i, block := range blocks {
+				got[i] = *block
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("readCoverProfile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package needle
+
+import (
+	"context"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+
+	"github.com/roidaradal/fn/ds"
+	"github.com/roidaradal/fn/lang"
+)
+
+// Method names of well-known standard library interfaces,
+// called outside the module (fmt, io, sort, encoding, errors, net/http)
+var wellKnownMethods = []string{
+	"String", "GoString", "Format", "Error", "Unwrap", "Is", "As",
+	"Read", "Write", "Close", "Seek", "ReadFrom", "WriteTo",
+	"Len", "Less", "Swap", "Push", "Pop",
+	"MarshalJSON", "UnmarshalJSON", "MarshalText", "UnmarshalText", "MarshalBinary", "UnmarshalBinary",
+	"Scan", "Value", "ServeHTTP",
+}
+
+// Unreferenced code: unexported symbols never referenced within their package,
+// and exported symbols of internal/ packages never referenced within the module
+type DeadCode struct {
+	Unexported []*Symbol `json:"unexported"` // unreferenced unexported symbols
+	Exported   []*Symbol `json:"exported"`   // unreferenced exported symbols of internal/ packages
+}
+
+// Create new DeadCode
+func newDeadCode() DeadCode {
+	return DeadCode{
+		Unexported: make([]*Symbol, 0),
+		Exported:   make([]*Symbol, 0),
+	}
+}
+
+// Add other dead code symbols
+func (d *DeadCode) add(other DeadCode) {
+	d.Unexported = append(d.Unexported, other.Unexported...)
+	d.Exported = append(d.Exported, other.Exported...)
+}
+
+// Total unreferenced symbols
+func (d DeadCode) Count() int {
+	return len(d.Unexported) + len(d.Exported)
+}
+
+// Check if package is under an internal/ folder: only importable within the module
+func isInternalPackage(pkgName string) bool {
+	return slices.Contains(strings.Split(pkgName, "/"), "internal")
+}
+
+// Find dead code with the type-checked files: a symbol is used if an identifier in the module
+// (code and test files) resolves to it, and a method is also used if it is required by an interface
+// that its type implements, or named like a method of a well-known standard library interface
+func computeDeadCode(ctx context.Context, mod *Module) error {
+	mod.Code.DeadCode = newDeadCode()
+	used := usedSymbols(mod)
+	wellKnown := ds.SetFrom(wellKnownMethods)
+
+	for _, pkg := range mod.Packages {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		pkg.DeadCode = newDeadCode()
+		isInternal := isInternalPackage(pkg.Name)
+		importPath := mod.ImportPath(pkg)
+		for _, symbol := range packageSymbols(mod, pkg) {
+			name := symbol.Name
+			isMethod := symbol.Type == PUB_METHOD || symbol.Type == PRIV_METHOD
+			if isMethod {
+				_, name, _ = strings.Cut(symbol.Name, ".")
+			}
+			isExported := ast.IsExported(name)
+			if isExported && !isInternal {
+				continue // may be used outside the module
+			}
+			if used.Has(importPath+"."+symbol.Name) || (isMethod && wellKnown.Has(name)) {
+				continue
+			}
+			if isExported {
+				pkg.DeadCode.Exported = append(pkg.DeadCode.Exported, symbol)
+			} else {
+				pkg.DeadCode.Unexported = append(pkg.DeadCode.Unexported, symbol)
+			}
+		}
+		mod.Code.DeadCode.add(pkg.DeadCode)
+	}
+	slices.SortFunc(mod.Code.DeadCode.Unexported, compareSymbols)
+	slices.SortFunc(mod.Code.DeadCode.Exported, compareSymbols)
+	return nil
+}
+
+// Keys of used module symbols (importPath.Name, importPath.Type.Method): objects of identifiers
+// in type-checked files, and methods of types required by the module interfaces they implement
+func usedSymbols(mod *Module) *ds.Set[string] {
+	checker := mod.checker
+	used := ds.NewSet[string]()
+	for _, pkg := range mod.Packages {
+		for _, f := range pkg.Files {
+			info := checker.fileInfo(f)
+			if info == nil {
+				continue
+			}
+			for _, obj := range info.Uses {
+				if key := checker.objectKey(obj); key != "" {
+					used.Add(key)
+				}
+			}
+		}
+	}
+	namedTypes := make(map[string]*NamedType) // type ID => NamedType
+	for _, t := range mod.TypeGraph.Types {
+		namedTypes[t.ID] = t
+	}
+	for _, t := range mod.TypeGraph.Types {
+		named := checker.named(t)
+		if named == nil {
+			continue
+		}
+		for _, ifaceID := range t.Implements {
+			iface := namedTypes[strings.TrimPrefix(ifaceID, "*")]
+			if iface == nil {
+				continue
+			}
+			for _, name := range iface.Methods {
+				// Own or promoted method: marks the method of the embedded type
+				method, _, _ := types.LookupFieldOrMethod(types.NewPointer(named), true, named.Obj().Pkg(), name)
+				if method, ok := method.(*types.Func); ok {
+					used.Add(checker.objectKey(method))
+				}
+			}
+		}
+	}
+	return used
+}
+
+// Top-level symbols declared in package code files:
+// functions, methods, types, consts, vars (except main, init, blank identifiers)
+func packageSymbols(mod *Module, pkg *Package) []*Symbol {
+	symbols := make([]*Symbol, 0)
+	add := func(name string, codeType CodeType, file *File, pos token.Pos) {
+		symbols = append(symbols, &Symbol{
+			Name:    name,
+			Type:    codeType,
+			Package: pkg.Name,
+			File:    file.Name,
+			Line:    mod.fset.Position(pos).Line,
+		})
+	}
+	for _, f := range pkg.Files {
+		if f.Type != FILE_CODE || f.syntax == nil {
+			continue
+		}
+		for _, decl := range f.syntax.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				name := d.Name.Name
+				isPublic := ast.IsExported(name)
+				if d.Recv != nil {
+					add(receiverTypeName(d)+"."+name, lang.Ternary(isPublic, PUB_METHOD, PRIV_METHOD), f, d.Pos())
+				} else if name != "init" && name != "_" && !(name == "main" && pkg.Type == PKG_MAIN) {
+					add(name, lang.Ternary(isPublic, PUB_FUNCTION, PRIV_FUNCTION), f, d.Pos())
+				}
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					switch s := spec.(type) {
+					case *ast.TypeSpec:
+						add(s.Name.Name, typeSpecCodeType(s), f, s.Pos())
+					case *ast.ValueSpec:
+						isConst := d.Tok == token.CONST
+						for _, ident := range s.Names {
+							if ident.Name == "_" {
+								continue
+							}
+							isPublic := ast.IsExported(ident.Name)
+							codeType := lang.Ternary(isConst,
+								lang.Ternary(isPublic, PUB_CONST, PRIV_CONST),
+								lang.Ternary(isPublic, PUB_VAR, PRIV_VAR),
+							)
+							add(ident.Name, codeType, f, ident.Pos())
+						}
+					}
+				}
+			}
+		}
+	}
+	return symbols
+}
@@ -0,0 +1,37 @@
+package needle
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestDeadCode(t *testing.T) {
+	mod, err := Analyze(context.Background(), "testdata/modules/deadcode", WithoutModuleGraph())
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := func(symbols []*Symbol) []string {
+		out := make([]string, len(symbols))
+		for i, symbol := range symbols {
+			out[i] = symbol.Package + ":" + symbol.Name
+		}
+		slices.Sort(out)
+		return out
+	}
+	tests := []struct {
+		name string
+		got  []*Symbol
+		want []string
+	}{
+		// memStore.flush shares its name with the called fileStore.flush;
+		// unusedVar is only shadowed by a local variable
+		{"unexported", mod.Code.DeadCode.Unexported, []string{"/:helper", "/:memStore.flush", "/:unusedVar"}},
+		{"exported", mod.Code.DeadCode.Exported, []string{"internal/util:Unused"}},
+	}
+	for _, tt := range tests {
+		if got := names(tt.got); !slices.Equal(got, tt.want) {
+			t.Errorf("%s dead code = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package needle
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"go/ast"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/roidaradal/fn/dict"
+	"github.com/roidaradal/fn/ds"
+	"github.com/roidaradal/fn/list"
+	"github.com/roidaradal/fn/str"
+)
+
+// Process the independent subpackages and dependency levels (dependency DAG)
+func computeDependencyLevels(ctx context.Context, mod *Module) error {
+	inbound := mod.Deps.InternalUsers // list of subpackages that use it
+	outbound := mod.Deps.Of           // list of subpackages it uses
+
+	// Compute independent subpackages
+	// Add depedency packages to queue
+	q := ds.NewQueue[string]()
+	for _, subPkg := range slices.Sorted(maps.Keys(mod.Nodes)) {
+		node := mod.Nodes[subPkg]
+		if node.FileCount() == 0 {
+			continue // skip no files
+		}
+		// Independent subpackage = no inbound & outbound
+		if len(inbound[subPkg]) == 0 && len(outbound[subPkg]) == 0 {
+			mod.Deps.Independent = append(mod.Deps.Independent, subPkg)
+		} else {
+			q.Enqueue(subPkg)
+		}
+	}
+	slices.Sort(mod.Deps.Independent)
+
+	// Compute tree subpackage levels
+	levelOf := make(dict.IntMap)
+	stalled := 0 // consecutive packages put back in queue
+	for q.NotEmpty() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		subPkg, _ := q.Dequeue()
+		if len(outbound[subPkg]) == 0 {
+			// no dependency = level 0
+			levelOf[subPkg] = 0
+			stalled = 0
+			continue
+		}
+		knownDeps := list.Filter(outbound[subPkg], func(dep string) bool {
+			return dict.HasKey(levelOf, dep)
+		})
+		// Whole queue stalled: import cycle through test files,
+		// break it at a package in the cycle, only considering dependencies with known levels
+		isCycle := stalled > q.Len() && isInCycle(subPkg, outbound, levelOf)
+		if len(knownDeps) == len(outbound[subPkg]) || isCycle {
+			// All dependencies have levels = get max + 1
+			depLevels := list.Translate(knownDeps, levelOf)
+			levelOf[subPkg] = slices.Max(append(depLevels, -1)) + 1
+			stalled = 0
+			continue
+		}
+		// Incomplete, put back in queue
+		q.Enqueue(subPkg)
+		stalled += 1
+	}
+	mod.Deps.Levels = dict.GroupByValue(levelOf)
+	dict.SortValues(mod.Deps.Levels)
+
+	return nil
+}
+
+// Compute the dependency graph layout
+func computeDependencyLayout(ctx context.Context, mod *Module) error {
+	mod.Deps.Nodes = make(dict.StringMap)
+	mod.Deps.Edges = make([]string, 0)
+
+	computeCoupling(mod)
+
+	// Add edge list
+	for pkg, deps := range mod.Deps.Of {
+		pkg = nodeToPackageName(pkg)
+		for _, dependency := range deps {
+			dependency = nodeToPackageName(dependency)
+			mod.Deps.Edges = append(mod.Deps.Edges, fmt.Sprintf("'%s-%s'", pkg, dependency))
+		}
+	}
+
+	for pkg, node := range dependencyPositions(mod) {
+		mod.Deps.Nodes[pkg] = fmt.Sprintf("{x : %d, y: %d, sink: %v}", node.X, node.Y, node.Sink)
+	}
+
+	return nil
+}
+
+// Check if package is in an import cycle of packages without levels
+func isInCycle(subPkg string, outbound dict.StringListMap, levelOf dict.IntMap) bool {
+	visited := ds.NewSet[string]()
+	stack := slices.Clone(outbound[subPkg])
+	for len(stack) > 0 {
+		dep := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if dep == subPkg {
+			return true
+		}
+		if visited.Has(dep) || dict.HasKey(levelOf, dep) {
+			continue
+		}
+		visited.Add(dep)
+		stack = append(stack, outbound[dep]...)
+	}
+	return false
+}
+
+// Dependency graph node: position and if package is a sink (level 0)
+type graphNode struct {
+	X    int  `json:"x"`
+	Y    int  `json:"y"`
+	Sink bool `json:"sink"`
+}
+
+// Compute the dependency graph node positions: one column per level, from the highest level
+func dependencyPositions(mod *Module) map[string]graphNode {
+	const columnWidth, rowHeight = 100, 100
+	const cellRadius = 50
+	nodes := make(map[string]graphNode)
+	numLevels := len(mod.Deps.Levels)
+	for col := range numLevels {
+		level := numLevels - col - 1
+		x := (columnWidth * col) + cellRadius
+		offset := 50 * (col % 2)
+		for row, pkg := range mod.Deps.Levels[level] {
+			y := (rowHeight * row) + cellRadius + offset
+			nodes[nodeToPackageName(pkg)] = graphNode{X: x, Y: y, Sink: level == 0}
+		}
+	}
+	return nodes
+}
+
+// Compute internal edge weights: distinct symbols referenced (pkg.Symbol selectors)
+// and number of files importing the dependency, from each package's files
+func computeCoupling(mod *Module) {
+	mod.Deps.Coupling = make([]*Coupling, 0)
+	for _, pkg := range mod.Packages {
+		symbols := make(map[string]*ds.Set[string]) // dependency => symbols
+		files := make(dict.Counter[string])         // dependency => file count
+		for _, f := range pkg.Files {
+			if f.syntax == nil {
+				continue
+			}
+			aliases := make(dict.StringMap) // import name => dependency package name
+			for _, spec := range f.syntax.Imports {
+				dep, isInternal := isInternalDependency(mod, spec.Path.Value)
+				dep = nodeToPackageName(dep)
+				if !isInternal || dep == pkg.Name {
+					continue
+				}
+				files[dep] += 1
+				if symbols[dep] == nil {
+					symbols[dep] = ds.NewSet[string]()
+				}
+				importPath := strings.Trim(spec.Path.Value, "\"")
+				name := importPath[strings.LastIndex(importPath, "/")+1:]
+				if spec.Name != nil {
+					name = spec.Name.Name
+				}
+				aliases[name] = dep
+			}
+			ast.Inspect(f.syntax, func(node ast.Node) bool {
+				sel, ok := node.(*ast.SelectorExpr)
+				if !ok {
+					return true
+				}
+				if x, ok := sel.X.(*ast.Ident); ok && x.Obj == nil {
+					if dep, ok := aliases[x.Name]; ok {
+						symbols[dep].Add(sel.Sel.Name)
+					}
+				}
+				return true
+			})
+		}
+		for dep, fileCount := range files {
+			depSymbols := symbols[dep].Items()
+			slices.Sort(depSymbols)
+			mod.Deps.Coupling = append(mod.Deps.Coupling, &Coupling{
+				From:    pkg.Name,
+				To:      dep,
+				Symbols: depSymbols,
+				Files:   fileCount,
+			})
+		}
+	}
+	slices.SortFunc(mod.Deps.Coupling, func(a, b *Coupling) int {
+		return cmp.Or(cmp.Compare(a.From, b.From), cmp.Compare(a.To, b.To))
+	})
+}
+
+// Add file dependency
+func (f *File) addDependency(mod *Module, dep string) {
+	if internalDep, ok := isInternalDependency(mod, dep); ok {
+		f.Deps[internalDep] = true
+		return
+	}
+	if externalDep, ok := isExternalDependency(mod, dep); ok {
+		f.Deps[externalDep] = false
+		return
+	}
+}
+
+// Check if package is internal dependency,
+// return the processed name
+func isInternalDependency(mod *Module, dep string) (string, bool) {
+	dep = strings.Trim(dep, "\"")
+	isInternal := startsWith(dep, mod.Name)
+	if isInternal {
+		dep = str.GuardWith(strings.TrimPrefix(dep, mod.Name), "/")
+	}
+	return dep, isInternal
+}
+
+// Check if package is external dependency,
+// return the processed name
+func isExternalDependency(mod *Module, dep string) (string, bool) {
+	dep = strings.Trim(dep, "\"")
+	for _, extPkg := range mod.Deps.External {
+		if startsWith(dep, extPkg) {
+			return extPkg, true
+		}
+	}
+	return dep, false
+}
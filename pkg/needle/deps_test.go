@@ -0,0 +1,47 @@
+package needle
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestCoupling(t *testing.T) {
+	mod, err := Analyze(context.Background(), "testdata/modules/deps", WithoutModuleGraph())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Coupling{
+		{From: "/", To: "a", Symbols: []string{"Run"}, Files: 1},
+		{From: "/", To: "b", Symbols: []string{"Store"}, Files: 1},
+		{From: "a", To: "b", Symbols: []string{"Load", "Save", "Store"}, Files: 2},
+		{From: "b", To: "a", Symbols: []string{"Run"}, Files: 1},
+	}
+	if len(mod.Deps.Coupling) != len(want) {
+		t.Fatalf("Coupling = %d edges, want %d", len(mod.Deps.Coupling), len(want))
+	}
+	for i, got := range mod.Deps.Coupling {
+		if got.From != want[i].From || got.To != want[i].To || got.Files != want[i].Files || !slices.Equal(got.Symbols, want[i].Symbols) {
+			t.Errorf("Coupling[%d] = %+v, want %+v", i, *got, want[i])
+		}
+	}
+}
+
+func TestDependencyLevelsCycle(t *testing.T) {
+	// Import cycle through test files (b_test.go imports a, a imports b),
+	// and external test package b_test importing b: levels must not hang,
+	// and the cycle is broken at a package in the cycle, not at the root package
+	mod, err := Analyze(context.Background(), "testdata/modules/deps", WithoutModuleGraph(), WithTimeout(5*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[int][]string{0: {"/a"}, 1: {"/b"}, 2: {"/"}}
+	if !maps.EqualFunc(mod.Deps.Levels, want, slices.Equal) {
+		t.Errorf("Levels = %v, want %v", mod.Deps.Levels, want)
+	}
+	if !slices.Equal(mod.Deps.Independent, []string{"/c"}) {
+		t.Errorf("Independent = %v, want [/c]", mod.Deps.Independent)
+	}
+}
@@ -0,0 +1,6 @@
+// Package needle analyzes a Go module: its internal and external dependencies,
+// module statistics, and code composition.
+//
+// Use Analyze to build the Module object, then render it with BuildReport
+// or consume the exported types (JSON-tagged) with your own renderer.
+package needle
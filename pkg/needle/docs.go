@@ -0,0 +1,255 @@
+package needle
+
+import (
+	"cmp"
+	"go/ast"
+	"go/token"
+	"slices"
+	"strings"
+
+	"github.com/roidaradal/fn/dict"
+	"github.com/roidaradal/fn/lang"
+	"github.com/roidaradal/fn/list"
+	"github.com/roidaradal/fn/number"
+)
+
+// Documentation coverage of exported identifiers
+type DocProfile struct {
+	PackageDocs  int                    `json:"packageDocs"`  // number of packages with package doc comment
+	Exported     dict.Counter[CodeType] `json:"exported"`     // exported identifier counts
+	Documented   dict.Counter[CodeType] `json:"documented"`   // documented exported identifier counts
+	Undocumented []*Symbol              `json:"undocumented"` // exported identifiers without proper doc comment
+}
+
+// Code identifier location
+type Symbol struct {
+	Name    string   `json:"name"`    // identifier name, Type.Method for methods
+	Type    CodeType `json:"type"`    // identifier type
+	Package string   `json:"package"` // package name
+	File    string   `json:"file"`    // file name
+	Line    int      `json:"line"`    // line number
+}
+
+// Create new DocProfile
+func newDocProfile() DocProfile {
+	return DocProfile{
+		Exported:     make(dict.Counter[CodeType]),
+		Documented:   make(dict.Counter[CodeType]),
+		Undocumented: make([]*Symbol, 0),
+	}
+}
+
+// Add other doc profile counts and undocumented symbols
+func (p *DocProfile) add(other DocProfile) {
+	p.PackageDocs += other.PackageDocs
+	dict.UpdateCounts(p.Exported, other.Exported)
+	dict.UpdateCounts(p.Documented, other.Documented)
+	p.Undocumented = append(p.Undocumented, other.Undocumented...)
+}
+
+// Total exported identifiers
+func (p DocProfile) ExportedCount() int {
+	return list.Sum(dict.Values(p.Exported))
+}
+
+// Total documented exported identifiers
+func (p DocProfile) DocumentedCount() int {
+	return list.Sum(dict.Values(p.Documented))
+}
+
+// Percentage of documented exported identifiers (100 if no exported identifiers)
+func (p DocProfile) Coverage() float64 {
+	total := p.ExportedCount()
+	if total == 0 {
+		return 100
+	}
+	return number.Ratio(p.DocumentedCount()*100, total)
+}
+
+// Build doc profile of file syntax tree: exported functions, methods, types, consts, vars
+func newFileDocProfile(mod *Module, pkgName string, file *File) DocProfile {
+	p := newDocProfile()
+	check := func(name string, codeType CodeType, pos token.Pos, doc *ast.CommentGroup, docName string) {
+		p.Exported[codeType] += 1
+		if isProperDoc(doc, docName) {
+			p.Documented[codeType] += 1
+			return
+		}
+		p.Undocumented = append(p.Undocumented, &Symbol{
+			Name:    name,
+			Type:    codeType,
+			Package: pkgName,
+			File:    file.Name,
+			Line:    mod.fset.Position(pos).Line,
+		})
+	}
+
+	for _, decl := range file.syntax.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			name := d.Name.Name
+			if !ast.IsExported(name) {
+				continue
+			}
+			if d.Recv == nil {
+				check(name, PUB_FUNCTION, d.Pos(), d.Doc, name)
+				continue
+			}
+			// Skip methods of private types, not part of API
+			recvType := receiverTypeName(d)
+			if ast.IsExported(recvType) {
+				check(recvType+"."+name, PUB_METHOD, d.Pos(), d.Doc, name)
+			}
+		case *ast.GenDecl:
+			isGroup := d.Lparen.IsValid()
+			for _, spec := range d.Specs {
+				// Spec in group: own doc comment, or any group doc comment of an iota const block (enum)
+				// Single spec: declaration doc comment
+				doc := lang.Ternary(isGroup, specDoc(spec), d.Doc)
+				groupDoc := isGroup && d.Doc != nil && doc == nil && isIotaBlock(d)
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					if ast.IsExported(s.Name.Name) {
+						codeType := classifyTypeSpec(s)
+						if groupDoc {
+							p.Exported[codeType] += 1
+							p.Documented[codeType] += 1
+						} else {
+							check(s.Name.Name, codeType, s.Pos(), doc, s.Name.Name)
+						}
+					}
+				case *ast.ValueSpec:
+					codeType := lang.Ternary(d.Tok == token.CONST, PUB_CONST, PUB_VAR)
+					for _, ident := range s.Names {
+						if !ast.IsExported(ident.Name) {
+							continue
+						}
+						if groupDoc {
+							p.Exported[codeType] += 1
+							p.Documented[codeType] += 1
+						} else {
+							check(ident.Name, codeType, ident.Pos(), doc, ident.Name)
+						}
+					}
+				}
+			}
+		}
+	}
+	return p
+}
+
+// Check if doc comment starts with identifier name (optionally preceded by A, An, The)
+func isProperDoc(doc *ast.CommentGroup, name string) bool {
+	if doc == nil {
+		return false
+	}
+	text := doc.Text()
+	for _, article := range []string{"A ", "An ", "The "} {
+		text = strings.TrimPrefix(text, article)
+	}
+	if !startsWith(text, name) {
+		return false
+	}
+	// Name must be a whole word: Foo is not documented by // Foobar
+	rest := strings.TrimPrefix(text, name)
+	return rest == "" || !isIdentChar(rest[0])
+}
+
+// Check if declaration is a const block that uses iota
+func isIotaBlock(decl *ast.GenDecl) bool {
+	if decl.Tok != token.CONST {
+		return false
+	}
+	for _, spec := range decl.Specs {
+		s, ok := spec.(*ast.ValueSpec)
+		if !ok {
+			continue
+		}
+		for _, value := range s.Values {
+			usesIota := false
+			ast.Inspect(value, func(node ast.Node) bool {
+				ident, ok := node.(*ast.Ident)
+				usesIota = usesIota || (ok && ident.Name == "iota")
+				return !usesIota
+			})
+			if usesIota {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Get doc comment of type or value spec
+func specDoc(spec ast.Spec) *ast.CommentGroup {
+	switch s := spec.(type) {
+	case *ast.TypeSpec:
+		return s.Doc
+	case *ast.ValueSpec:
+		return s.Doc
+	}
+	return nil
+}
+
+// Get receiver type name of method, without pointer and type parameters
+func receiverTypeName(fn *ast.FuncDecl) string {
+	return baseTypeName(fn.Recv.List[0].Type)
+}
+
+// Get type name of type expression, without pointer and type parameters; blank if not a local named type
+func baseTypeName(expr ast.Expr) string {
+	for {
+		switch e := expr.(type) {
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.IndexListExpr:
+			expr = e.X
+		case *ast.Ident:
+			return e.Name
+		default:
+			return ""
+		}
+	}
+}
+
+// Classify public type spec as struct, interface, or alias
+func classifyTypeSpec(spec *ast.TypeSpec) CodeType {
+	switch spec.Type.(type) {
+	case *ast.StructType:
+		return PUB_STRUCT
+	case *ast.InterfaceType:
+		return PUB_INTERFACE
+	}
+	return PUB_ALIAS
+}
+
+// Check if byte can be part of an identifier
+func isIdentChar(b byte) bool {
+	return b == '_' || ('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z') || ('0' <= b && b <= '9')
+}
+
+// Compute package doc profile from its code files (test files excluded)
+func (pkg *Package) computeDocProfile(mod *Module) {
+	pkg.Docs = newDocProfile()
+	for _, f := range pkg.Files {
+		if f.Type == FILE_TEST || f.syntax == nil {
+			continue
+		}
+		if f.syntax.Doc != nil {
+			pkg.Docs.PackageDocs = 1
+		}
+		pkg.Docs.add(newFileDocProfile(mod, pkg.Name, f))
+	}
+	slices.SortFunc(pkg.Docs.Undocumented, compareSymbols)
+}
+
+// Sort symbols by package, file, line
+func compareSymbols(a, b *Symbol) int {
+	return cmp.Or(
+		cmp.Compare(a.Package, b.Package),
+		cmp.Compare(a.File, b.File),
+		cmp.Compare(a.Line, b.Line),
+	)
+}
@@ -0,0 +1,45 @@
+package needle
+
+import (
+	"go/parser"
+	"testing"
+)
+
+func TestFileDocProfile(t *testing.T) {
+	tests := []struct {
+		name       string
+		src        string
+		exported   int
+		documented int
+	}{
+		{"own doc", "// Foo does it\nfunc Foo() {}", 1, 1},
+		{"doc without name", "// does it\nfunc Foo() {}", 1, 0},
+		{"article", "// A Foo is a foo\ntype Foo int", 1, 1},
+		{"longer name", "// Foobar does it\nfunc Foo() {}", 1, 0},
+		{"single spec", "// Max is the limit\nconst Max = 1", 1, 1},
+		{"const group doc", "// Limits\nconst (\n\tMin = 0\n\tMax = 1\n)", 2, 0},
+		{"var group doc", "// Defaults\nvar (\n\tA = 1\n\tB = 2\n)", 2, 0},
+		{"type group doc", "// Types\ntype (\n\tA int\n\tB int\n)", 2, 0},
+		{"group own docs", "const (\n\t// Min is the lower limit\n\tMin = 0\n\tMax = 1\n)", 2, 1},
+		{"iota group doc", "// Colors\nconst (\n\tRed = iota\n\tGreen\n)", 2, 2},
+		{"iota expression group doc", "// Sizes\nconst (\n\tKB = 1 << (10 * (iota + 1))\n\tMB\n)", 2, 2},
+		{"iota group own doc", "// Colors\nconst (\n\tRed = iota\n\t// Blue is not green\n\tGreen\n)", 2, 1},
+		{"unexported", "const (\n\tmin = 0\n)\nfunc foo() {}", 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mod := newModule()
+			file := &File{Name: "a.go"}
+			syntax, err := parser.ParseFile(mod.fset, "a.go", "package a\n\n"+tt.src, parser.ParseComments)
+			if err != nil {
+				t.Fatal(err)
+			}
+			file.syntax = syntax
+			p := newFileDocProfile(mod, "a", file)
+			if p.ExportedCount() != tt.exported || p.DocumentedCount() != tt.documented {
+				t.Errorf("exported, documented = %d, %d, want %d, %d",
+					p.ExportedCount(), p.DocumentedCount(), tt.exported, tt.documented)
+			}
+		})
+	}
+}
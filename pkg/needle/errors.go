@@ -0,0 +1,261 @@
+package needle
+
+import (
+	"context"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"regexp"
+	"strings"
+)
+
+// Error handling profile
+type ErrorProfile struct {
+	Checks           int `json:"checks"`           // if err != nil, if err == nil, errors.Is / errors.As
+	WrappedReturns   int `json:"wrappedReturns"`   // return ..., fmt.Errorf("...%w...", err)
+	FormattedReturns int `json:"formattedReturns"` // return ..., fmt.Errorf(...) without %w
+	NewReturns       int `json:"newReturns"`       // return ..., errors.New(...)
+	BareReturns      int `json:"bareReturns"`      // return ..., err
+	WrapVerbs        int `json:"wrapVerbs"`        // %w usage in fmt.Errorf
+	Ignored          int `json:"ignored"`          // _ = f(), x, _ := f(), where _ is an error result
+	Unchecked        int `json:"unchecked"`        // f() call statement, where the last result of f is an error (module functions)
+	Panics           int `json:"panics"`           // panic(...) calls
+}
+
+var (
+	// Error check line: if err != nil {, if x, err := f(); err == nil {, if errors.Is(err, ...) {
+	errCheckLine = regexp.MustCompile(`^if (.*; )?(.*\b(err\d*|\w+Err) [!=]= nil\b|.*\berrors\.(Is|As)\().* \{$`)
+	// Error identifier: err, err2, readErr (not Stderr, errors)
+	errIdent = regexp.MustCompile(`^(err\d*|\w+Err)$`)
+)
+
+// Check if line starts an error check block
+func isErrorCheckLine(cleanLine string) bool {
+	return errCheckLine.MatchString(cleanLine)
+}
+
+// Add other error profile counts
+func (p *ErrorProfile) add(other ErrorProfile) {
+	p.Checks += other.Checks
+	p.WrappedReturns += other.WrappedReturns
+	p.FormattedReturns += other.FormattedReturns
+	p.NewReturns += other.NewReturns
+	p.BareReturns += other.BareReturns
+	p.WrapVerbs += other.WrapVerbs
+	p.Ignored += other.Ignored
+	p.Unchecked += other.Unchecked
+	p.Panics += other.Panics
+}
+
+// Total error returns
+func (p ErrorProfile) Returns() int {
+	return p.WrappedReturns + p.FormattedReturns + p.NewReturns + p.BareReturns
+}
+
+// Build error profile of file syntax tree, except Ignored and Unchecked (need type info)
+func newErrorProfile(syntax *ast.File) ErrorProfile {
+	var p ErrorProfile
+	ast.Inspect(syntax, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.IfStmt:
+			if isErrorCheck(n.Cond) {
+				p.Checks += 1
+			}
+		case *ast.ReturnStmt:
+			if len(n.Results) > 0 {
+				p.addReturn(n.Results[len(n.Results)-1])
+			}
+		case *ast.CallExpr:
+			if isCallTo(n, "", "panic") {
+				p.Panics += 1
+			} else if isCallTo(n, "fmt", "Errorf") && hasWrapVerb(n) {
+				p.WrapVerbs += 1
+			}
+		}
+		return true
+	})
+	return p
+}
+
+// Classify the last result of a return statement
+func (p *ErrorProfile) addReturn(result ast.Expr) {
+	switch r := result.(type) {
+	case *ast.Ident:
+		if errIdent.MatchString(r.Name) {
+			p.BareReturns += 1
+		}
+	case *ast.CallExpr:
+		if isCallTo(r, "fmt", "Errorf") {
+			if hasWrapVerb(r) {
+				p.WrappedReturns += 1
+			} else {
+				p.FormattedReturns += 1
+			}
+		} else if isCallTo(r, "errors", "New") {
+			p.NewReturns += 1
+		}
+	}
+}
+
+// Count unchecked calls and ignored error results in the type-checked files of each package,
+// and add them to the file, package and module error profiles
+func computeErrorCalls(ctx context.Context, mod *Module) error {
+	for _, pkg := range mod.Packages {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		for _, f := range pkg.Files {
+			info := mod.checker.fileInfo(f)
+			if info == nil {
+				continue
+			}
+			var calls ErrorProfile
+			calls.addCalls(f.syntax, info)
+			f.Errors.add(calls)
+			pkg.Errors.add(calls)
+			mod.Code.Errors.add(calls)
+		}
+	}
+	return nil
+}
+
+// Count call statements whose last result is an error, and assignments of error results to the blank identifier
+func (p *ErrorProfile) addCalls(syntax *ast.File, info *types.Info) {
+	ast.Inspect(syntax, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.DeferStmt, *ast.GoStmt:
+			return false // deferred and goroutine calls can't be checked
+		case *ast.ExprStmt:
+			call, ok := n.X.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			results := errorResults(info, call)
+			if len(results) > 0 && results[len(results)-1] {
+				p.Unchecked += 1
+			}
+		case *ast.AssignStmt:
+			if isIgnoredError(n, info) {
+				p.Ignored += 1
+			}
+		}
+		return true
+	})
+}
+
+// Get whether each result of call is an error, nil if the call type is unknown
+// (conversion, or function of an import outside the module)
+func errorResults(info *types.Info, call *ast.CallExpr) []bool {
+	if fun, ok := info.Types[call.Fun]; !ok || fun.IsType() {
+		return nil
+	}
+	tv, ok := info.Types[call]
+	if !ok || tv.Type == nil {
+		return nil
+	}
+	switch t := tv.Type.(type) {
+	case *types.Tuple:
+		results := make([]bool, t.Len())
+		for i := range t.Len() {
+			results[i] = isErrorType(t.At(i).Type())
+		}
+		return results
+	case *types.Basic:
+		if t.Kind() == types.Invalid {
+			return nil
+		}
+	}
+	return []bool{isErrorType(tv.Type)}
+}
+
+// Check if type is the error interface
+func isErrorType(t types.Type) bool {
+	return types.Identical(t, types.Universe.Lookup("error").Type())
+}
+
+// Check if call is pkg.Func() of an imported package
+func isImportedCall(info *types.Info, call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	x, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	_, isPackage := info.Uses[x].(*types.PkgName)
+	return isPackage
+}
+
+// Check if condition is an error check: err != nil, err == nil, errors.Is / errors.As
+func isErrorCheck(cond ast.Expr) bool {
+	found := false
+	ast.Inspect(cond, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.BinaryExpr:
+			if n.Op != token.EQL && n.Op != token.NEQ {
+				return true
+			}
+			x, ok1 := n.X.(*ast.Ident)
+			y, ok2 := n.Y.(*ast.Ident)
+			if ok1 && ok2 && errIdent.MatchString(x.Name) && y.Name == "nil" {
+				found = true
+			}
+		case *ast.CallExpr:
+			if isCallTo(n, "errors", "Is") || isCallTo(n, "errors", "As") {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// Check if assignment ignores an error: the blank identifier in the position of an error result,
+// or _ = pkg.Func() if the imported function is outside the module (single result assumed)
+func isIgnoredError(assign *ast.AssignStmt, info *types.Info) bool {
+	if len(assign.Rhs) != 1 {
+		return false
+	}
+	call, ok := assign.Rhs[0].(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	results := errorResults(info, call)
+	if results == nil {
+		return isImportedCall(info, call) && len(assign.Lhs) == 1 && isBlank(assign.Lhs[0])
+	}
+	for i, lhs := range assign.Lhs {
+		if i < len(results) && results[i] && isBlank(lhs) {
+			return true
+		}
+	}
+	return false
+}
+
+// Check if expression is the blank identifier
+func isBlank(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == "_"
+}
+
+// Check if call is pkgName.funcName(...), or funcName(...) if pkgName is blank
+func isCallTo(call *ast.CallExpr, pkgName, funcName string) bool {
+	switch fn := call.Fun.(type) {
+	case *ast.Ident:
+		return pkgName == "" && fn.Name == funcName
+	case *ast.SelectorExpr:
+		pkg, ok := fn.X.(*ast.Ident)
+		return ok && pkg.Name == pkgName && fn.Sel.Name == funcName
+	}
+	return false
+}
+
+// Check if fmt.Errorf format string has %w verb
+func hasWrapVerb(call *ast.CallExpr) bool {
+	if len(call.Args) == 0 {
+		return false
+	}
+	format, ok := call.Args[0].(*ast.BasicLit)
+	return ok && format.Kind == token.STRING && strings.Contains(format.Value, "%w")
+}
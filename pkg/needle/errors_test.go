@@ -0,0 +1,57 @@
+package needle
+
+import (
+	"context"
+	"testing"
+)
+
+func TestErrorCalls(t *testing.T) {
+	mod, err := Analyze(context.Background(), "testdata/modules/errors", WithoutModuleGraph())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		pkg       string
+		file      string
+		unchecked int
+		ignored   int
+	}{
+		{"/", "main.go", 3, 4},
+		{"/", "main_test.go", 1, 0},
+		{"store", "store.go", 0, 0},
+		{"store", "store_test.go", 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			file := findFile(mod, tt.pkg, tt.file)
+			if file == nil {
+				t.Fatalf("file %s/%s not found", tt.pkg, tt.file)
+			}
+			if file.Errors.Unchecked != tt.unchecked || file.Errors.Ignored != tt.ignored {
+				t.Errorf("%s unchecked, ignored = %d, %d, want %d, %d",
+					tt.file, file.Errors.Unchecked, file.Errors.Ignored, tt.unchecked, tt.ignored)
+			}
+		})
+	}
+	if got := mod.Code.Errors.Unchecked; got != 5 {
+		t.Errorf("module unchecked = %d, want 5", got)
+	}
+	if got := mod.Code.Errors.Ignored; got != 4 {
+		t.Errorf("module ignored = %d, want 4", got)
+	}
+}
+
+// Find file of module package by names, nil if not found
+func findFile(mod *Module, pkgName, fileName string) *File {
+	for _, pkg := range mod.Packages {
+		if pkg.Name != pkgName {
+			continue
+		}
+		for _, f := range pkg.Files {
+			if f.Name == fileName {
+				return f
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package needle
+
+import (
+	"context"
+
+	"github.com/roidaradal/fn/io"
+)
+
+// Size and composition of an external module, analyzed from its source in the module cache
+type ModuleWeight struct {
+	Packages  int `json:"packages"`
+	Files     int `json:"files"`     // Go files, test files included
+	Lines     int `json:"lines"`     // all lines of Go files
+	CodeLines int `json:"codeLines"` // lines of code: comments and blank lines excluded
+	External  int `json:"external"`  // its own direct external dependencies
+}
+
+// Analyze direct external dependencies (if enabled) from their source in the module cache:
+// same code analysis as the main module, without its module graph, licenses, nested external analysis
+// and call graph. Modules not downloaded in the module cache are skipped
+func analyzeExternalModules(ctx context.Context, mod *Module) error {
+	if !mod.options.AnalyzeExternal {
+		return nil
+	}
+	logger := mod.options.Logger
+	for _, m := range mod.ModuleGraph.Modules {
+		if !m.Direct {
+			continue
+		}
+		dir := mod.moduleDir(goModRequire{path: m.Path, version: m.Version})
+		if !io.IsDir(dir) {
+			logger.Warn("module source not found in module cache", "module", m.Path, "version", m.Version)
+			continue
+		}
+		dep, err := Analyze(ctx, dir,
+			WithWorkers(mod.options.Workers),
+			WithLogger(logger),
+			WithModCache(mod.options.ModCache),
+			WithoutModuleGraph(),
+			WithoutCallGraph(),
+		)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			logger.Warn("external module analysis failed", "module", m.Path, "error", err)
+			continue
+		}
+		m.Analysis = dep
+		m.Weight = &ModuleWeight{
+			Packages:  dep.PackageCount,
+			Files:     dep.FileCount,
+			Lines:     dep.LineCount,
+			CodeLines: dep.Code.Lines[LINE_CODE],
+			External:  len(dep.Deps.External),
+		}
+	}
+	return nil
+}
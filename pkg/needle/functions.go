@@ -0,0 +1,198 @@
+package needle
+
+import (
+	"cmp"
+	"fmt"
+	"go/ast"
+	"math"
+	"slices"
+
+	"github.com/roidaradal/fn/dict"
+	"github.com/roidaradal/fn/lang"
+)
+
+// Go function or method
+type Function struct {
+	Name      string                 `json:"name"`      // function name, Type.Method for methods
+	Type      CodeType               `json:"type"`      // (public/private) x (function/method)
+	Package   string                 `json:"package"`   // package name
+	File      string                 `json:"file"`      // file name
+	Line      int                    `json:"line"`      // line number of function header
+	LineCount int                    `json:"lineCount"` // header to closing brace
+	Lines     dict.Counter[LineType] `json:"lines"`     // line type counts
+	MaxDepth  int                    `json:"maxDepth"`  // max nesting depth of if / for / switch / select blocks
+	Coverage  Coverage               `json:"coverage"`  // statement coverage, if cover profile given
+	Callers   []string               `json:"callers"`   // IDs of module functions that call this function
+	Callees   []string               `json:"callees"`   // IDs of module functions called by this function
+}
+
+// Histogram bucket: [Min, Max] range
+type Bucket struct {
+	Min int `json:"min"`
+	Max int `json:"max"` // math.MaxInt = no upper bound
+}
+
+var (
+	LengthBuckets = []Bucket{{1, 10}, {11, 20}, {21, 40}, {41, 80}, {81, math.MaxInt}}
+	DepthBuckets  = []Bucket{{0, 0}, {1, 1}, {2, 2}, {3, 3}, {4, 4}, {5, math.MaxInt}}
+)
+
+// Build functions of code file syntax tree
+func newFileFunctions(mod *Module, pkgName string, file *File) []*Function {
+	functions := make([]*Function, 0)
+	for _, decl := range file.syntax.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		name := fn.Name.Name
+		isPublic := ast.IsExported(name)
+		codeType := lang.Ternary(isPublic, PUB_FUNCTION, PRIV_FUNCTION)
+		if fn.Recv != nil {
+			name = receiverTypeName(fn) + "." + name
+			codeType = lang.Ternary(isPublic, PUB_METHOD, PRIV_METHOD)
+		}
+		start := mod.fset.Position(fn.Pos()).Line
+		end := mod.fset.Position(fn.End()).Line
+		function := &Function{
+			Name:      name,
+			Type:      codeType,
+			Package:   pkgName,
+			File:      file.Name,
+			Line:      start,
+			LineCount: end - start + 1,
+			Lines:     make(dict.Counter[LineType]),
+			MaxDepth:  maxNestingDepth(fn.Body, 0),
+			Callers:   make([]string, 0),
+			Callees:   make([]string, 0),
+		}
+		// Line numbers start at 1, file.Lines index starts at 0
+		for i := start - 1; i < end && i < len(file.Lines); i++ {
+			function.Lines[file.Lines[i].Type] += 1
+		}
+		functions = append(functions, function)
+	}
+	return functions
+}
+
+// Compute max nesting depth of if / for / range / switch / select blocks under node.
+// Else-if chains stay at the same depth; function literals are part of the enclosing function
+func maxNestingDepth(node ast.Node, depth int) int {
+	if node == nil {
+		return depth
+	}
+	maxDepth := depth
+	ast.Inspect(node, func(n ast.Node) bool {
+		if n == node {
+			return true
+		}
+		var children []ast.Node
+		switch s := n.(type) {
+		case *ast.IfStmt:
+			children = []ast.Node{s.Body}
+			// else-if: same depth as if
+			for s.Else != nil {
+				elseIf, ok := s.Else.(*ast.IfStmt)
+				if !ok {
+					children = append(children, s.Else)
+					break
+				}
+				children = append(children, elseIf.Body)
+				s = elseIf
+			}
+		case *ast.ForStmt:
+			children = []ast.Node{s.Body}
+		case *ast.RangeStmt:
+			children = []ast.Node{s.Body}
+		case *ast.SwitchStmt:
+			children = []ast.Node{s.Body}
+		case *ast.TypeSwitchStmt:
+			children = []ast.Node{s.Body}
+		case *ast.SelectStmt:
+			children = []ast.Node{s.Body}
+		default:
+			return true
+		}
+		for _, child := range children {
+			maxDepth = max(maxDepth, maxNestingDepth(child, depth+1))
+		}
+		return false // children already visited
+	})
+	return maxDepth
+}
+
+// Compute package functions from its code files (test files excluded)
+func (pkg *Package) computeFunctions(mod *Module) {
+	pkg.Functions = make([]*Function, 0)
+	for _, f := range pkg.Files {
+		if f.Type == FILE_TEST || f.syntax == nil {
+			continue
+		}
+		pkg.Functions = append(pkg.Functions, newFileFunctions(mod, pkg.Name, f)...)
+	}
+	slices.SortFunc(pkg.Functions, compareFunctions)
+}
+
+// Return functions with more than maxLines lines, sorted by descending line count
+func (mod Module) LongFunctions(maxLines int) []*Function {
+	functions := make([]*Function, 0)
+	for _, pkg := range mod.Packages {
+		for _, fn := range pkg.Functions {
+			if fn.LineCount > maxLines {
+				functions = append(functions, fn)
+			}
+		}
+	}
+	slices.SortFunc(functions, func(a, b *Function) int {
+		return cmp.Or(cmp.Compare(b.LineCount, a.LineCount), compareFunctions(a, b))
+	})
+	return functions
+}
+
+// Return all module functions
+func (mod Module) Functions() []*Function {
+	functions := make([]*Function, 0)
+	for _, pkg := range mod.Packages {
+		functions = append(functions, pkg.Functions...)
+	}
+	slices.SortFunc(functions, compareFunctions)
+	return functions
+}
+
+// Compute histogram of values: count per bucket
+func histogram(values []int, buckets []Bucket) []int {
+	counts := make([]int, len(buckets))
+	for _, value := range values {
+		for i, bucket := range buckets {
+			if bucket.Contains(value) {
+				counts[i] += 1
+				break
+			}
+		}
+	}
+	return counts
+}
+
+// Check if value is in bucket range
+func (b Bucket) Contains(value int) bool {
+	return b.Min <= value && value <= b.Max
+}
+
+// Bucket label: 11-20, 81+, 3
+func (b Bucket) String() string {
+	if b.Max == math.MaxInt {
+		return fmt.Sprintf("%d+", b.Min)
+	} else if b.Min == b.Max {
+		return fmt.Sprintf("%d", b.Min)
+	}
+	return fmt.Sprintf("%d-%d", b.Min, b.Max)
+}
+
+// Sort functions by package, file, line
+func compareFunctions(a, b *Function) int {
+	return cmp.Or(
+		cmp.Compare(a.Package, b.Package),
+		cmp.Compare(a.File, b.File),
+		cmp.Compare(a.Line, b.Line),
+	)
+}
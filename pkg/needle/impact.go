@@ -0,0 +1,158 @@
+package needle
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/roidaradal/fn/ds"
+)
+
+// Packages affected by a change in some module packages
+type Impact struct {
+	Changed   []string `json:"changed"`   // changed package names
+	Packages  []string `json:"packages"`  // changed packages and their transitive internal users (through code files)
+	Mains     []string `json:"mains"`     // affected main packages: binaries to rebuild
+	Tests     []string `json:"tests"`     // packages with test files to rerun
+	TestFiles []string `json:"testFiles"` // test files to rerun: affected package test files, and test files importing an affected package
+}
+
+// Compute impact of changing the given packages: package names ("a/b", "/" for root),
+// node names ("/a/b") or import paths are accepted.
+// Only code file imports are followed transitively; a test file importing an affected package
+// marks its package tests for rerun, without affecting the package users
+func (mod Module) Impact(packages []string) (*Impact, error) {
+	lookup := make(map[string]*Package)
+	for _, pkg := range mod.Packages {
+		lookup[pkg.Name] = pkg
+	}
+	changed := ds.NewSet[string]()
+	for _, name := range packages {
+		pkgName := mod.packageName(name)
+		if _, ok := lookup[pkgName]; !ok {
+			return nil, fmt.Errorf("package %q not found in module %q", name, mod.Name)
+		}
+		changed.Add(pkgName)
+	}
+
+	// Internal package => packages whose code files import it
+	codeUsers := make(map[string][]string)
+	for _, pkg := range mod.Packages {
+		for _, f := range pkg.Files {
+			if f.Type == FILE_TEST {
+				continue
+			}
+			for dep, isInternal := range f.Deps {
+				dep = nodeToPackageName(dep)
+				if isInternal && dep != pkg.Name && !slices.Contains(codeUsers[dep], pkg.Name) {
+					codeUsers[dep] = append(codeUsers[dep], pkg.Name)
+				}
+			}
+		}
+	}
+
+	// Transitive users of changed packages
+	affected := ds.NewSet[string]()
+	q := ds.QueueFrom(changed.Items())
+	for q.NotEmpty() {
+		pkgName, _ := q.Dequeue()
+		if affected.Has(pkgName) {
+			continue
+		}
+		affected.Add(pkgName)
+		for _, user := range codeUsers[pkgName] {
+			q.Enqueue(user)
+		}
+	}
+
+	impact := &Impact{
+		Changed:   changed.Items(),
+		Packages:  affected.Items(),
+		Mains:     make([]string, 0),
+		Tests:     make([]string, 0),
+		TestFiles: make([]string, 0),
+	}
+	for _, pkg := range mod.Packages {
+		isAffected := affected.Has(pkg.Name)
+		if isAffected && pkg.Type == PKG_MAIN {
+			impact.Mains = append(impact.Mains, pkg.Name)
+		}
+		hasTests := false
+		for _, f := range pkg.Files {
+			if f.Type != FILE_TEST {
+				continue
+			}
+			isRerun := isAffected
+			for dep, isInternal := range f.Deps {
+				isRerun = isRerun || (isInternal && affected.Has(nodeToPackageName(dep)))
+			}
+			if isRerun {
+				impact.TestFiles = append(impact.TestFiles, packageFilePath(pkg.Name, f.Name))
+				hasTests = true
+			}
+		}
+		if hasTests {
+			impact.Tests = append(impact.Tests, pkg.Name)
+		}
+	}
+	slices.Sort(impact.Changed)
+	slices.Sort(impact.Packages)
+	slices.Sort(impact.Mains)
+	slices.Sort(impact.Tests)
+	slices.Sort(impact.TestFiles)
+	return impact, nil
+}
+
+// Get package names of changed files (relative to module path, or absolute):
+// Go files belong to the package of their folder, testdata files to the package above testdata/,
+// go.mod and go.sum changes affect all packages; other files are ignored
+func (mod Module) FilePackages(files []string) []string {
+	isPackage := ds.NewSet[string]()
+	for _, pkg := range mod.Packages {
+		isPackage.Add(pkg.Name)
+	}
+	packages := ds.NewSet[string]()
+	for _, file := range files {
+		file = filepath.ToSlash(file)
+		if filepath.IsAbs(file) {
+			if absPath, err := filepath.Abs(mod.Path); err == nil {
+				if relPath, err := filepath.Rel(absPath, file); err == nil {
+					file = filepath.ToSlash(relPath)
+				}
+			}
+		}
+		file = path.Clean(file)
+		if file == "go.mod" || file == "go.sum" {
+			names := mod.PackageNames()
+			slices.Sort(names)
+			return names
+		}
+		folder := path.Dir(file)
+		if before, _, ok := strings.Cut("/"+folder+"/", "/testdata/"); ok {
+			folder = strings.TrimPrefix(before, "/")
+		} else if !strings.HasSuffix(file, ".go") {
+			continue
+		}
+		if folder == "." {
+			folder = "/"
+		}
+		pkgName := nodeToPackageName(folder)
+		if isPackage.Has(pkgName) {
+			packages.Add(pkgName)
+		}
+	}
+	names := packages.Items()
+	slices.Sort(names)
+	return names
+}
+
+// Normalize package reference to package name: import path, node name (/a/b), or package name
+func (mod Module) packageName(name string) string {
+	if name == mod.Name {
+		return "/"
+	}
+	name = strings.TrimPrefix(name, mod.Name+"/")
+	return nodeToPackageName(strings.TrimSuffix(name, "/"))
+}
@@ -0,0 +1,311 @@
+package needle
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/roidaradal/fn/lang"
+)
+
+// Unclassified license identifier
+const LICENSE_UNKNOWN = "Unknown"
+
+// Detected license of an external module
+type License struct {
+	SPDX     []string `json:"spdx"`     // SPDX expressions of license files, sorted (Unknown if not classified)
+	Files    []string `json:"files"`    // license files at module root
+	Copyleft bool     `json:"copyleft"` // a license file requires GPL family or MPL in all its alternatives
+	Unknown  bool     `json:"unknown"`  // no license file, unclassified license, or source not in module cache
+	NoSource bool     `json:"noSource"` // module source not in module cache
+}
+
+// License policy violation
+type LicenseViolation struct {
+	Module string `json:"module"`
+	Reason string `json:"reason"`
+}
+
+// License text fingerprint: all phrases must appear (whitespace-normalized, case-insensitive)
+type licenseRule struct {
+	spdx     string
+	isTitle  bool // phrases must appear in the license head (first lines), not in the body
+	phrases  []string
+	excludes []string // phrases that must not appear
+}
+
+// License fingerprints, more specific licenses first. Licenses that name other licenses
+// in their body (MPL-2.0 names the GPL family, GPL-3.0 names AGPL-3.0) are matched by title
+var licenseRules = []licenseRule{
+	{"MPL-2.0", true, []string{"mozilla public license", "2.0"}, nil},
+	{"AGPL-3.0", true, []string{"gnu affero general public license", "version 3"}, nil},
+	{"LGPL-3.0", true, []string{"gnu lesser general public license", "version 3"}, nil},
+	{"LGPL-2.1", true, []string{"gnu lesser general public license", "version 2.1"}, nil},
+	{"GPL-3.0", true, []string{"gnu general public license", "version 3"}, nil},
+	{"GPL-2.0", true, []string{"gnu general public license", "version 2"}, nil},
+	{"Apache-2.0", true, []string{"apache license", "version 2.0"}, nil},
+	{"MIT", false, []string{"permission is hereby granted, free of charge"}, nil},
+	{"ISC", false, []string{"permission to use, copy, modify, and/or distribute this software for any purpose"}, nil},
+	{"BSD-3-Clause", false, []string{"redistribution and use in source and binary forms", "neither the name"}, nil},
+	{"BSD-2-Clause", false, []string{"redistribution and use in source and binary forms"}, []string{"neither the name"}},
+	{"Unlicense", false, []string{"this is free and unencumbered software released into the public domain"}, nil},
+	{"CC0-1.0", false, []string{"cc0 1.0 universal"}, nil},
+}
+
+// Number of non-blank lines of the license head, where license titles are matched
+const licenseHeadLines = 5
+
+// Copyleft license identifier prefixes
+var copyleftLicenses = []string{"GPL-", "LGPL-", "AGPL-", "MPL-"}
+
+var (
+	licenseFilePattern = regexp.MustCompile(`(?i)^(licen[cs]e|copying)([-._].*)?$`)
+	spdxPattern        = regexp.MustCompile(`SPDX-License-Identifier:[ \t]*([^\r\n]+)`)
+	spdxIDPattern      = regexp.MustCompile(`^[A-Za-z0-9.+-]+$`)
+)
+
+// Detect licenses of direct external dependencies from their source in the module cache
+func detectLicenses(ctx context.Context, mod *Module) error {
+	for _, m := range mod.ModuleGraph.Modules {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !m.Direct {
+			continue
+		}
+		dir := mod.moduleDir(goModRequire{path: m.Path, version: m.Version})
+		m.License = detectLicense(dir)
+	}
+	return nil
+}
+
+// Detect license of module source folder: classify license files at its root
+func detectLicense(dir string) *License {
+	license := &License{
+		SPDX:  make([]string, 0),
+		Files: make([]string, 0),
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		license.Unknown = true
+		license.NoSource = true
+		return license
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !licenseFilePattern.MatchString(entry.Name()) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		license.Files = append(license.Files, entry.Name())
+		spdx := classifyLicense(string(data))
+		if !slices.Contains(license.SPDX, spdx) {
+			license.SPDX = append(license.SPDX, spdx)
+		}
+	}
+	slices.Sort(license.SPDX)
+	license.Unknown = len(license.SPDX) == 0 || slices.Contains(license.SPDX, LICENSE_UNKNOWN)
+	for _, spdx := range license.SPDX {
+		license.Copyleft = license.Copyleft || isCopyleftLicense(spdx)
+	}
+	return license
+}
+
+// Classify license text: SPDX-License-Identifier expression, or license fingerprints
+func classifyLicense(text string) string {
+	if match := spdxPattern.FindStringSubmatch(text); match != nil {
+		// Remove comment end: /* SPDX-License-Identifier: MIT */
+		expr := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(match[1]), "*/"))
+		expr = strings.Join(strings.Fields(expr), " ")
+		if _, err := parseSPDXExpression(expr); err == nil {
+			return expr
+		}
+	}
+	lines := make([]string, 0, licenseHeadLines)
+	for line := range strings.Lines(text) {
+		if strings.TrimSpace(line) != "" && len(lines) < licenseHeadLines {
+			lines = append(lines, line)
+		}
+	}
+	head := normalizeLicenseText(strings.Join(lines, " "))
+	text = normalizeLicenseText(text)
+	for _, rule := range licenseRules {
+		target := lang.Ternary(rule.isTitle, head, text)
+		isMatch := true
+		for _, phrase := range rule.phrases {
+			isMatch = isMatch && strings.Contains(target, phrase)
+		}
+		for _, phrase := range rule.excludes {
+			isMatch = isMatch && !strings.Contains(target, phrase)
+		}
+		if isMatch {
+			return rule.spdx
+		}
+	}
+	return LICENSE_UNKNOWN
+}
+
+// Normalize license text for fingerprints: lowercase, single spaces
+func normalizeLicenseText(text string) string {
+	return strings.ToLower(strings.Join(strings.Fields(text), " "))
+}
+
+// Parse SPDX license expression into alternatives (OR) of required licenses (AND), without exceptions (WITH):
+// MIT OR (Apache-2.0 AND BSD-3-Clause) => [[MIT] [Apache-2.0 BSD-3-Clause]]
+func parseSPDXExpression(expr string) ([][]string, error) {
+	expr = strings.NewReplacer("(", " ( ", ")", " ) ").Replace(expr)
+	p := &spdxParser{tokens: strings.Fields(expr)}
+	alternatives, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q in SPDX expression", p.tokens[p.pos])
+	}
+	return alternatives, nil
+}
+
+// SPDX expression parser: or = and {OR and}, and = license {AND license}, license = id [WITH id] | (or)
+type spdxParser struct {
+	tokens []string
+	pos    int
+}
+
+// Consume next token if it is the given operator (case-insensitive)
+func (p *spdxParser) accept(operator string) bool {
+	if p.pos < len(p.tokens) && strings.EqualFold(p.tokens[p.pos], operator) {
+		p.pos += 1
+		return true
+	}
+	return false
+}
+
+// Parse OR expression: alternatives of each operand
+func (p *spdxParser) parseOr() ([][]string, error) {
+	alternatives, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("OR") {
+		other, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		alternatives = append(alternatives, other...)
+	}
+	return alternatives, nil
+}
+
+// Parse AND expression: each alternative of an operand combined with each alternative of the others
+func (p *spdxParser) parseAnd() ([][]string, error) {
+	alternatives, err := p.parseLicense()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("AND") {
+		other, err := p.parseLicense()
+		if err != nil {
+			return nil, err
+		}
+		combined := make([][]string, 0, len(alternatives)*len(other))
+		for _, a := range alternatives {
+			for _, b := range other {
+				combined = append(combined, append(slices.Clone(a), b...))
+			}
+		}
+		alternatives = combined
+	}
+	return alternatives, nil
+}
+
+// Parse license identifier (with optional exception), or parenthesized expression
+func (p *spdxParser) parseLicense() ([][]string, error) {
+	if p.accept("(") {
+		alternatives, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, fmt.Errorf("missing ) in SPDX expression")
+		}
+		return alternatives, nil
+	}
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("missing license in SPDX expression")
+	}
+	id := p.tokens[p.pos]
+	if !spdxIDPattern.MatchString(id) || slices.ContainsFunc([]string{"AND", "OR", "WITH"}, func(op string) bool {
+		return strings.EqualFold(id, op)
+	}) {
+		return nil, fmt.Errorf("invalid license %q in SPDX expression", id)
+	}
+	p.pos += 1
+	if p.accept("WITH") {
+		if p.pos >= len(p.tokens) || !spdxIDPattern.MatchString(p.tokens[p.pos]) {
+			return nil, fmt.Errorf("missing exception in SPDX expression")
+		}
+		p.pos += 1 // exceptions only add permissions
+	}
+	return [][]string{{id}}, nil
+}
+
+// Get license alternatives of SPDX expression; an unparsed expression is a single license
+func spdxAlternatives(spdx string) [][]string {
+	alternatives, err := parseSPDXExpression(spdx)
+	if err != nil {
+		return [][]string{{spdx}}
+	}
+	return alternatives
+}
+
+// Check if license is copyleft (GPL family, MPL): every alternative requires a copyleft license
+func isCopyleftLicense(spdx string) bool {
+	return !slices.ContainsFunc(spdxAlternatives(spdx), func(licenses []string) bool {
+		return !slices.ContainsFunc(licenses, func(id string) bool {
+			return slices.ContainsFunc(copyleftLicenses, func(prefix string) bool {
+				return startsWith(strings.ToUpper(id), prefix)
+			})
+		})
+	})
+}
+
+// Check if license is allowed: all licenses of any alternative are allowed
+func isAllowedLicense(spdx string, allowed []string) bool {
+	return slices.ContainsFunc(spdxAlternatives(spdx), func(licenses []string) bool {
+		return !slices.ContainsFunc(licenses, func(id string) bool {
+			return !slices.ContainsFunc(allowed, func(a string) bool { return strings.EqualFold(a, id) })
+		})
+	})
+}
+
+// Check direct external dependency licenses against allowed SPDX identifiers:
+// a module passes if any of its licenses is allowed; unknown licenses are violations
+func (mod Module) LicenseViolations(allowed []string) []*LicenseViolation {
+	violations := make([]*LicenseViolation, 0)
+	for _, m := range mod.ModuleGraph.Modules {
+		if !m.Direct || m.License == nil {
+			continue
+		}
+		var reason string
+		switch {
+		case m.License.NoSource:
+			reason = "source not in module cache: license unknown"
+		case len(m.License.Files) == 0:
+			reason = "no license file"
+		case !slices.ContainsFunc(m.License.SPDX, func(spdx string) bool {
+			return isAllowedLicense(spdx, allowed)
+		}):
+			reason = fmt.Sprintf("license %s not allowed", strings.Join(m.License.SPDX, ", "))
+		default:
+			continue
+		}
+		violations = append(violations, &LicenseViolation{Module: m.Path, Reason: reason})
+	}
+	return violations
+}
@@ -0,0 +1,111 @@
+package needle
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestClassifyLicenseTexts(t *testing.T) {
+	tests := []struct {
+		file string
+		want string
+	}{
+		{"MIT.txt", "MIT"},
+		{"BSD-2-Clause.txt", "BSD-2-Clause"},
+		{"BSD-3-Clause.txt", "BSD-3-Clause"},
+		{"BSD-3-Clause-Go.txt", "BSD-3-Clause"},
+		{"Apache-2.0.txt", "Apache-2.0"},
+		{"MPL-2.0.txt", "MPL-2.0"}, // names AGPL-3.0 and the GPL family in section 1.12
+		{"LGPL-2.1.txt", "LGPL-2.1"},
+		{"LGPL-3.0.txt", "LGPL-3.0"},
+		{"GPL-2.0.txt", "GPL-2.0"},
+		{"GPL-3.0.txt", "GPL-3.0"}, // names AGPL-3.0 in section 13
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", "licenses", tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := classifyLicense(string(data)); got != tt.want {
+				t.Errorf("classifyLicense(%s) = %q, want %q", tt.file, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassifyLicenseSPDX(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"SPDX-License-Identifier: MIT\n", "MIT"},
+		{"// SPDX-License-Identifier: MIT OR Apache-2.0\n", "MIT OR Apache-2.0"},
+		{"/* SPDX-License-Identifier: (MIT AND BSD-3-Clause)  OR GPL-2.0+ */\n", "(MIT AND BSD-3-Clause) OR GPL-2.0+"},
+		{"SPDX-License-Identifier: Apache-2.0 WITH LLVM-exception\r\n", "Apache-2.0 WITH LLVM-exception"},
+		{"SPDX-License-Identifier: MIT OR\nnot a license\n", LICENSE_UNKNOWN},
+		{"All rights reserved.\n", LICENSE_UNKNOWN},
+	}
+	for _, tt := range tests {
+		if got := classifyLicense(tt.text); got != tt.want {
+			t.Errorf("classifyLicense(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestParseSPDXExpression(t *testing.T) {
+	tests := []struct {
+		expr    string
+		want    [][]string
+		wantErr bool
+	}{
+		{"MIT", [][]string{{"MIT"}}, false},
+		{"MIT OR Apache-2.0", [][]string{{"MIT"}, {"Apache-2.0"}}, false},
+		{"MIT AND (Apache-2.0 OR BSD-2-Clause)", [][]string{{"MIT", "Apache-2.0"}, {"MIT", "BSD-2-Clause"}}, false},
+		{"mit or apache-2.0", [][]string{{"mit"}, {"apache-2.0"}}, false},
+		{"GPL-2.0-only WITH Classpath-exception-2.0", [][]string{{"GPL-2.0-only"}}, false},
+		{"", nil, true},
+		{"MIT OR", nil, true},
+		{"(MIT", nil, true},
+		{"MIT Apache-2.0", nil, true},
+		{"MIT WITH", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := parseSPDXExpression(tt.expr)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseSPDXExpression(%q) error = %v, want error %v", tt.expr, err, tt.wantErr)
+			continue
+		}
+		if !slices.EqualFunc(got, tt.want, slices.Equal) {
+			t.Errorf("parseSPDXExpression(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestLicensePolicy(t *testing.T) {
+	allowed := []string{"MIT", "MPL-2.0"}
+	tests := []struct {
+		spdx       string
+		isAllowed  bool
+		isCopyleft bool
+	}{
+		{"MIT", true, false},
+		{"mpl-2.0", true, true},
+		{"Apache-2.0", false, false},
+		{"MIT OR GPL-3.0", true, false},
+		{"MIT AND Apache-2.0", false, false},
+		{"GPL-2.0 OR LGPL-2.1", false, true},
+		{"MIT AND GPL-3.0", false, true},
+		{LICENSE_UNKNOWN, false, false},
+	}
+	for _, tt := range tests {
+		if got := isAllowedLicense(tt.spdx, allowed); got != tt.isAllowed {
+			t.Errorf("isAllowedLicense(%q) = %v, want %v", tt.spdx, got, tt.isAllowed)
+		}
+		if got := isCopyleftLicense(tt.spdx); got != tt.isCopyleft {
+			t.Errorf("isCopyleftLicense(%q) = %v, want %v", tt.spdx, got, tt.isCopyleft)
+		}
+	}
+}
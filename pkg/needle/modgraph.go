@@ -0,0 +1,432 @@
+package needle
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/roidaradal/fn/ds"
+)
+
+// External module of the module graph, resolved offline from go.mod files in the module cache
+type ExternalModule struct {
+	Path     string        `json:"path"`
+	Version  string        `json:"version"`           // selected version: highest required version
+	Direct   bool          `json:"direct"`            // required by the main go.mod, without // indirect
+	Requires []string      `json:"requires"`          // module paths required by its go.mod, sorted (nil if not loaded)
+	Via      []string      `json:"via"`               // direct dependencies that (transitively) require it, sorted
+	Versions []string      `json:"versions"`          // required versions in the module graph, sorted
+	Missing  bool          `json:"missing"`           // go.mod not found in the module cache
+	License  *License      `json:"license,omitempty"` // license of direct dependency
+	Weight   *ModuleWeight `json:"weight,omitempty"`  // size of direct dependency, if analyzed
+	Analysis *Module       `json:"-"`                 // analysis of direct dependency, if analyzed
+}
+
+// Transitive module graph of external dependencies (go mod graph, with module graph pruning)
+type ModuleGraph struct {
+	Modules []*ExternalModule   `json:"modules"` // third-party modules, sorted by path
+	Majors  map[string][]string `json:"majors"`  // module path without major version => paths of its major versions (if more than one)
+}
+
+// Parsed go.mod file: Go version, requirements, replacements
+type goModFile struct {
+	goVersion string
+	requires  []goModRequire
+	replaces  map[string]goModReplace // module path or path@version => replacement
+}
+
+// go.mod require line
+type goModRequire struct {
+	path     string
+	version  string
+	indirect bool
+}
+
+// go.mod replace target: module version, or local directory if version is blank
+type goModReplace struct {
+	path    string
+	version string
+}
+
+// Count of third-party modules: direct and indirect
+func (g ModuleGraph) Count() (direct, indirect int) {
+	for _, m := range g.Modules {
+		if m.Direct {
+			direct += 1
+		} else {
+			indirect += 1
+		}
+	}
+	return direct, indirect
+}
+
+// Resolve the module graph from the main go.mod and go.mod files in the module cache (no network).
+// As with module graph pruning, the requirements of a module are followed only if the main module
+// requires it, or if it is reached through a module whose go.mod predates Go 1.17
+func computeModuleGraph(ctx context.Context, mod *Module) error {
+	mod.ModuleGraph = ModuleGraph{
+		Modules: make([]*ExternalModule, 0),
+		Majors:  make(map[string][]string),
+	}
+	mainMod, err := readGoMod(filepath.Join(mod.Path, "go.mod"))
+	if err != nil {
+		return err
+	}
+	mod.goMod = mainMod
+
+	modules := make(map[string]*ExternalModule) // module path => ExternalModule
+	versions := make(map[string]*ds.Set[string])
+	requires := make(map[string]*ds.Set[string]) // module path => required module paths
+	addVersion := func(req goModRequire) *ExternalModule {
+		m, ok := modules[req.path]
+		if !ok {
+			m = &ExternalModule{Path: req.path, Version: req.version}
+			modules[req.path] = m
+			versions[req.path] = ds.NewSet[string]()
+		}
+		versions[req.path].Add(req.version)
+		if compareVersions(req.version, m.Version) > 0 {
+			m.Version = req.version
+		}
+		return m
+	}
+
+	// Load go.mod of required module versions: breadth-first, each path@version once
+	type entry struct {
+		req    goModRequire
+		expand bool // follow its requirements
+	}
+	queue := ds.NewQueue[entry]()
+	for _, req := range mainMod.requires {
+		m := addVersion(req)
+		m.Direct = m.Direct || !req.indirect
+		queue.Enqueue(entry{req, true})
+	}
+	loaded := ds.NewSet[string]()
+	missing := ds.NewSet[string]()
+	for queue.NotEmpty() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		e, _ := queue.Dequeue()
+		key := e.req.path + "@" + e.req.version
+		if !e.expand || loaded.Has(key) {
+			continue
+		}
+		loaded.Add(key)
+		depMod, err := readGoMod(mod.goModPath(e.req))
+		if err != nil {
+			missing.Add(key)
+			continue
+		}
+		if requires[e.req.path] == nil {
+			requires[e.req.path] = ds.NewSet[string]()
+		}
+		isPruned := compareVersions("v"+depMod.goVersion, "v1.17") >= 0
+		for _, req := range depMod.requires {
+			if req.path == mod.Name {
+				continue // dependency cycle back to the main module
+			}
+			addVersion(req)
+			requires[e.req.path].Add(req.path)
+			// Pruned module graph: requirements of a Go 1.17+ module are complete, not followed
+			queue.Enqueue(entry{req, !isPruned})
+		}
+	}
+	if !missing.IsEmpty() {
+		mod.options.Logger.Warn("go.mod files not found in module cache", "modules", missing.Len(), "modCache", mod.options.ModCache)
+	}
+
+	for path, m := range modules {
+		m.Versions = versions[path].Items()
+		slices.SortFunc(m.Versions, compareVersions)
+		m.Missing = missing.Has(path + "@" + m.Version)
+		if requires[path] != nil {
+			m.Requires = requires[path].Items()
+			slices.Sort(m.Requires)
+		}
+		mod.ModuleGraph.Modules = append(mod.ModuleGraph.Modules, m)
+	}
+	slices.SortFunc(mod.ModuleGraph.Modules, func(a, b *ExternalModule) int {
+		return cmp.Compare(a.Path, b.Path)
+	})
+
+	// Via: direct dependencies from which the module is reachable
+	for _, direct := range mod.ModuleGraph.Modules {
+		if !direct.Direct {
+			continue
+		}
+		seen := ds.NewSet[string]()
+		queue := ds.QueueFrom([]string{direct.Path})
+		for queue.NotEmpty() {
+			path, _ := queue.Dequeue()
+			for _, dep := range modules[path].Requires {
+				if !seen.Has(dep) && dep != direct.Path {
+					seen.Add(dep)
+					queue.Enqueue(dep)
+				}
+			}
+		}
+		for _, path := range seen.Items() {
+			if !modules[path].Direct {
+				modules[path].Via = append(modules[path].Via, direct.Path)
+			}
+		}
+	}
+
+	// Duplicate major versions
+	majors := make(map[string][]string)
+	for _, m := range mod.ModuleGraph.Modules {
+		slices.Sort(m.Via)
+		base := modulePathBase(m.Path)
+		majors[base] = append(majors[base], m.Path)
+	}
+	for base, paths := range majors {
+		if len(paths) > 1 {
+			mod.ModuleGraph.Majors[base] = paths
+		}
+	}
+	return nil
+}
+
+// Get go.mod path of required module version: in replacement directory,
+// or in the module cache download folder (has go.mod files of all versions in the module graph)
+func (mod Module) goModPath(req goModRequire) string {
+	req, dir := mod.replace(req)
+	if dir != "" {
+		return filepath.Join(dir, "go.mod")
+	}
+	return filepath.Join(mod.options.ModCache, "cache", "download", escapeModulePath(req.path), "@v", escapeModulePath(req.version)+".mod")
+}
+
+// Get source folder of required module version: replacement directory,
+// or extracted module in the module cache (only if downloaded)
+func (mod Module) moduleDir(req goModRequire) string {
+	req, dir := mod.replace(req)
+	if dir != "" {
+		return dir
+	}
+	return filepath.Join(mod.options.ModCache, escapeModulePath(req.path)+"@"+escapeModulePath(req.version))
+}
+
+// Apply main go.mod replace directives to required module version:
+// return replacement module version, or local directory
+func (mod Module) replace(req goModRequire) (goModRequire, string) {
+	if mod.goMod == nil {
+		return req, ""
+	}
+	target, ok := mod.goMod.replaces[req.path+"@"+req.version]
+	if !ok {
+		target, ok = mod.goMod.replaces[req.path]
+	}
+	if !ok {
+		return req, ""
+	}
+	if target.version == "" {
+		dir := target.path
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(mod.Path, dir)
+		}
+		return req, dir
+	}
+	return goModRequire{path: target.path, version: target.version}, ""
+}
+
+// Read and parse go.mod file: go version, require and replace directives
+func readGoMod(path string) (*goModFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	gomod := &goModFile{
+		requires: make([]goModRequire, 0),
+		replaces: make(map[string]goModReplace),
+	}
+	block := "" // directive of current ( block
+	for line := range strings.Lines(string(data)) {
+		line, comment, _ := strings.Cut(line, "//")
+		isIndirect := strings.TrimSpace(comment) == "indirect"
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == ")" {
+			block = ""
+			continue
+		}
+		directive := block
+		if directive == "" {
+			directive, fields = fields[0], fields[1:]
+			if len(fields) == 1 && fields[0] == "(" {
+				block = directive
+				continue
+			}
+		}
+		switch directive {
+		case "go":
+			if len(fields) > 0 {
+				gomod.goVersion = fields[0]
+			}
+		case "require":
+			if len(fields) >= 2 {
+				gomod.requires = append(gomod.requires, goModRequire{
+					path:     unquoteModulePath(fields[0]),
+					version:  fields[1],
+					indirect: isIndirect,
+				})
+			}
+		case "replace":
+			// old [version] => new [version]
+			arrow := slices.Index(fields, "=>")
+			if arrow < 1 || arrow == len(fields)-1 {
+				continue
+			}
+			old := unquoteModulePath(fields[0])
+			if arrow == 2 {
+				old += "@" + fields[1]
+			}
+			target := goModReplace{path: unquoteModulePath(fields[arrow+1])}
+			if arrow+2 < len(fields) {
+				target.version = fields[arrow+2]
+			}
+			gomod.replaces[old] = target
+		}
+	}
+	if gomod.goVersion == "" {
+		gomod.goVersion = "1.16" // no go directive: assume pre-pruning module
+	}
+	return gomod, nil
+}
+
+// Unquote go.mod module path, if quoted
+func unquoteModulePath(path string) string {
+	if unquoted, err := strconv.Unquote(path); err == nil {
+		return unquoted
+	}
+	return path
+}
+
+// Escape module path or version for the module cache: uppercase letters become ! + lowercase
+func escapeModulePath(path string) string {
+	var b strings.Builder
+	for _, r := range path {
+		if 'A' <= r && r <= 'Z' {
+			b.WriteByte('!')
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Get module path without major version suffix: /vN, or .vN for gopkg.in
+func modulePathBase(path string) string {
+	sep := "/v"
+	if startsWith(path, "gopkg.in/") {
+		sep = ".v"
+	}
+	i := strings.LastIndex(path, sep)
+	if i < 0 {
+		return path
+	}
+	if _, err := strconv.Atoi(path[i+2:]); err != nil {
+		return path
+	}
+	return path[:i]
+}
+
+// Compare semantic versions (vMAJOR.MINOR.PATCH[-prerelease][+build]) by precedence;
+// pseudo-versions compare as prereleases
+func compareVersions(a, b string) int {
+	coreA, preA := splitVersion(a)
+	coreB, preB := splitVersion(b)
+	for i := range 3 {
+		if c := cmp.Compare(coreA[i], coreB[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case preA == preB:
+		return 0
+	case preA == "":
+		return 1 // release > prerelease
+	case preB == "":
+		return -1
+	}
+	partsA, partsB := strings.Split(preA, "."), strings.Split(preB, ".")
+	for i := 0; i < len(partsA) && i < len(partsB); i++ {
+		numA, errA := strconv.Atoi(partsA[i])
+		numB, errB := strconv.Atoi(partsB[i])
+		var c int
+		switch {
+		case errA == nil && errB == nil:
+			c = cmp.Compare(numA, numB)
+		case errA == nil:
+			c = -1 // numeric < alphanumeric
+		case errB == nil:
+			c = 1
+		default:
+			c = cmp.Compare(partsA[i], partsB[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(partsA), len(partsB))
+}
+
+// Split version into [major, minor, patch] numbers and prerelease
+func splitVersion(version string) ([3]int, string) {
+	var core [3]int
+	version = strings.TrimPrefix(version, "v")
+	version, _, _ = strings.Cut(version, "+")
+	version, pre, _ := strings.Cut(version, "-")
+	for i, part := range strings.SplitN(version, ".", 3) {
+		core[i], _ = strconv.Atoi(part)
+	}
+	return core, pre
+}
+
+// Render module graph as a text tree from the main module's requirements:
+// each module's requirements are listed once, later occurrences are marked with (*)
+func (g ModuleGraph) Tree(root string) []string {
+	modules := make(map[string]*ExternalModule)
+	for _, m := range g.Modules {
+		modules[m.Path] = m
+	}
+	lines := []string{root}
+	expanded := ds.NewSet[string]()
+	var walk func(paths []string, prefix string)
+	walk = func(paths []string, prefix string) {
+		for i, path := range paths {
+			m := modules[path]
+			isLast := i == len(paths)-1
+			branch, indent := "├── ", "│   "
+			if isLast {
+				branch, indent = "└── ", "    "
+			}
+			label := fmt.Sprintf("%s %s", m.Path, m.Version)
+			if expanded.Has(path) && len(m.Requires) > 0 {
+				lines = append(lines, prefix+branch+label+" (*)")
+				continue
+			}
+			lines = append(lines, prefix+branch+label)
+			expanded.Add(path)
+			walk(m.Requires, prefix+indent)
+		}
+	}
+	// Roots: direct dependencies, and indirect ones not required by them
+	roots := make([]string, 0)
+	for _, m := range g.Modules {
+		if m.Direct || len(m.Via) == 0 {
+			roots = append(roots, m.Path)
+		}
+	}
+	walk(roots, "")
+	return lines
+}
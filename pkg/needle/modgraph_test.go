@@ -0,0 +1,148 @@
+package needle
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"v1.2.3", "v1.2.3", 0},
+		{"v1.2.3", "v1.10.0", -1},
+		{"v2.0.0", "v1.99.99", 1},
+		{"v1.17", "v1.17.0", 0},
+		{"v1.16", "v1.17", -1},
+		{"v1.21.0", "v1.17", 1},
+		{"v1.0.0-rc.1", "v1.0.0", -1},
+		{"v1.0.0", "v1.0.0-rc.1", 1},
+		{"v1.0.0-alpha", "v1.0.0-alpha.1", -1},
+		{"v1.0.0-alpha.1", "v1.0.0-alpha.beta", -1}, // numeric < alphanumeric
+		{"v1.0.0-beta.2", "v1.0.0-beta.11", -1},
+		{"v1.0.0-alpha", "v1.0.0-beta", -1},
+		{"v1.0.0+build.1", "v1.0.0+build.2", 0},
+		{"v0.0.0-20240101000000-abcdef123456", "v0.0.0-20250101000000-123456abcdef", -1}, // pseudo-versions
+		{"v0.0.0-20240101000000-abcdef123456", "v0.1.0", -1},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestReadGoMod(t *testing.T) {
+	tests := []struct {
+		name      string
+		gomod     string
+		goVersion string
+		requires  []goModRequire
+		replaces  map[string]goModReplace
+	}{
+		{
+			name:      "block",
+			gomod:     "module example.com/m\n\ngo 1.22\n\nrequire (\n\texample.com/a v1.0.0\n\texample.com/b v0.2.0 // indirect\n)\n",
+			goVersion: "1.22",
+			requires: []goModRequire{
+				{path: "example.com/a", version: "v1.0.0"},
+				{path: "example.com/b", version: "v0.2.0", indirect: true},
+			},
+		},
+		{
+			name:      "single line",
+			gomod:     "module example.com/m\ngo 1.21.5\nrequire example.com/a v1.0.0\nrequire example.com/b v0.2.0 // indirect\n",
+			goVersion: "1.21.5",
+			requires: []goModRequire{
+				{path: "example.com/a", version: "v1.0.0"},
+				{path: "example.com/b", version: "v0.2.0", indirect: true},
+			},
+		},
+		{
+			name:      "quoted path and comments",
+			gomod:     "// header comment\nmodule \"example.com/m\"\ngo 1.20\nrequire (\n\t\"example.com/a\" v1.0.0 // pinned\n)\n",
+			goVersion: "1.20",
+			requires:  []goModRequire{{path: "example.com/a", version: "v1.0.0"}},
+		},
+		{
+			name:      "no go directive",
+			gomod:     "module example.com/m\nrequire example.com/a v1.0.0\n",
+			goVersion: "1.16",
+			requires:  []goModRequire{{path: "example.com/a", version: "v1.0.0"}},
+		},
+		{
+			name:      "replace",
+			gomod:     "module example.com/m\ngo 1.22\nreplace example.com/a => ../a\nreplace (\n\texample.com/b v1.0.0 => example.com/fork/b v1.0.1\n\t\"example.com/c\" => \"example.com/fork/c\" v0.3.0\n\texample.com/d =>\n)\n",
+			goVersion: "1.22",
+			requires:  []goModRequire{},
+			replaces: map[string]goModReplace{
+				"example.com/a":        {path: "../a"},
+				"example.com/b@v1.0.0": {path: "example.com/fork/b", version: "v1.0.1"},
+				"example.com/c":        {path: "example.com/fork/c", version: "v0.3.0"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "go.mod")
+			if err := os.WriteFile(path, []byte(tt.gomod), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := readGoMod(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.goVersion != tt.goVersion {
+				t.Errorf("goVersion = %q, want %q", got.goVersion, tt.goVersion)
+			}
+			if !slices.Equal(got.requires, tt.requires) {
+				t.Errorf("requires = %+v, want %+v", got.requires, tt.requires)
+			}
+			if len(got.replaces) != len(tt.replaces) {
+				t.Errorf("replaces = %+v, want %+v", got.replaces, tt.replaces)
+			}
+			for old, want := range tt.replaces {
+				if got.replaces[old] != want {
+					t.Errorf("replaces[%s] = %+v, want %+v", old, got.replaces[old], want)
+				}
+			}
+		})
+	}
+	if _, err := readGoMod(filepath.Join(t.TempDir(), "go.mod")); err == nil {
+		t.Error("readGoMod(missing) = nil error, want error")
+	}
+}
+
+func TestModuleGraphPruning(t *testing.T) {
+	// legacy (no go directive) and example.com/Old (go 1.16) are unpruned: their requirements are followed;
+	// pruned (go 1.17) lists deep, but deep's requirements are not loaded
+	mod, err := Analyze(context.Background(), "testdata/modules/modgraph",
+		WithModCache(filepath.Join("testdata", "modcache")), WithoutLicenses(), WithoutCallGraph())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ExternalModule{
+		{Path: "example.com/Old", Version: "v0.3.0", Requires: []string{"example.com/leaf"}, Via: []string{"example.com/legacy"}},
+		{Path: "example.com/deep", Version: "v1.1.0", Via: []string{"example.com/pruned"}},
+		{Path: "example.com/leaf", Version: "v0.1.0-beta", Requires: []string{}, Via: []string{"example.com/legacy"}},
+		{Path: "example.com/legacy", Version: "v1.0.0", Direct: true, Requires: []string{"example.com/Old"}},
+		{Path: "example.com/pruned", Version: "v1.2.0", Direct: true, Requires: []string{"example.com/deep"}},
+	}
+	if len(mod.ModuleGraph.Modules) != len(want) {
+		for _, m := range mod.ModuleGraph.Modules {
+			t.Logf("%+v", *m)
+		}
+		t.Fatalf("Modules = %d, want %d", len(mod.ModuleGraph.Modules), len(want))
+	}
+	for i, got := range mod.ModuleGraph.Modules {
+		w := want[i]
+		if got.Path != w.Path || got.Version != w.Version || got.Direct != w.Direct || got.Missing ||
+			(got.Requires == nil) != (w.Requires == nil) || !slices.Equal(got.Requires, w.Requires) || !slices.Equal(got.Via, w.Via) {
+			t.Errorf("Modules[%d] = %+v, want %+v", i, *got, w)
+		}
+	}
+}
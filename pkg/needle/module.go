@@ -1,10 +1,13 @@
 package needle
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/roidaradal/fn/dict"
 	"github.com/roidaradal/fn/ds"
@@ -12,8 +15,16 @@ import (
 	"github.com/roidaradal/fn/list"
 )
 
-// Build Module object for Go module at path
+// Build Module object for Go module at path, using default options.
+// Use Analyze for cancellation and options
 func BuildModule(path string) (*Module, error) {
+	return Analyze(context.Background(), path)
+}
+
+// Analyze the Go module at path: build the Module object
+// with its dependencies, stats, and code composition.
+// Returns an error (and no Module) if ctx is cancelled or times out.
+func Analyze(ctx context.Context, path string, opts ...Option) (*Module, error) {
 	// Remove trailing slash if necessary
 	path = strings.TrimSuffix(path, "/")
 	// Check if path is directory
@@ -24,27 +35,77 @@ func BuildModule(path string) (*Module, error) {
 	// Create Module object
 	mod := newModule()
 	mod.Path = path
+	mod.options = newOptions(opts...)
+	mod.workers = make(chan struct{}, mod.options.Workers)
+	mod.progress = newTracker(mod.options.Progress)
+	logger := mod.options.Logger
+	logger.Info("analysis started", "path", path, "workers", mod.options.Workers)
+
+	err := collectAnalyzers(mod)
+	if err != nil {
+		return nil, err
+	}
+
+	if mod.options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, mod.options.Timeout)
+		defer cancel()
+	}
 
 	// Apply decorator functions to Module
-	decorators := []func(*Module) error{
-		readGoModFile,           // module
-		buildModuleNodes,        // module
-		buildModuleTree,         // module
-		computeDependencyLevels, // deps
-		computeDependencyLayout, // deps
+	decorators := []struct {
+		stage Stage
+		apply func(context.Context, *Module) error
+	}{
+		{STAGE_GOMOD, readGoModFile},             // module
+		{STAGE_MODULES, computeModuleGraph},      // deps
+		{STAGE_LICENSES, detectLicenses},         // deps
+		{STAGE_FOLDERS, buildModuleNodes},        // module
+		{STAGE_PACKAGES, buildModuleTree},        // module
+		{STAGE_CHECK, typeCheckModule},           // types
+		{STAGE_ERRORS, computeErrorCalls},        // code
+		{STAGE_COVERAGE, applyCoverProfile},      // code
+		{STAGE_TYPES, computeTypeGraph},          // types
+		{STAGE_LEVELS, computeDependencyLevels},  // deps
+		{STAGE_LAYOUT, computeDependencyLayout},  // deps
+		{STAGE_DEADCODE, computeDeadCode},        // code
+		{STAGE_CALLS, computeCallGraph},          // calls
+		{STAGE_EXTERNAL, analyzeExternalModules}, // deps
+		{STAGE_EXTRA, runModuleAnalyzers},        // extra
 	}
 	for _, decorator := range decorators {
-		err := decorator(mod)
+		if mod.options.skipStage(decorator.stage) {
+			logger.Debug("stage skipped", "stage", decorator.stage)
+			continue
+		}
+		mod.progress.setStage(decorator.stage)
+		start := time.Now()
+		err := ctx.Err()
+		if err == nil {
+			err = decorator.apply(ctx, mod)
+		}
 		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				err = fmt.Errorf("analysis of %q stopped: %w", path, ctxErr)
+			}
+			logger.Debug("stage failed", "stage", decorator.stage, "error", err)
 			return nil, err
 		}
+		logger.Debug("stage done", "stage", decorator.stage, "duration", time.Since(start))
 	}
+	mod.progress.setStage(STAGE_DONE)
+	logger.Info("analysis done",
+		"packages", mod.Stats.PackageCount,
+		"files", mod.Stats.FileCount,
+		"lines", mod.Stats.LineCount,
+		"duration", time.Since(mod.progress.start),
+	)
 
 	return mod, nil
 }
 
 // Read go.mod file to get module name and list of direct, external dependencies
-func readGoModFile(mod *Module) error {
+func readGoModFile(ctx context.Context, mod *Module) error {
 	// Ensure go.mod file exists
 	path := filepath.Join(mod.Path, "go.mod")
 	if !io.PathExists(path) {
@@ -86,12 +147,15 @@ func readGoModFile(mod *Module) error {
 }
 
 // Build module nodes, going through folders and subfolders
-func buildModuleNodes(mod *Module) error {
-	rootNode, err := buildNode(mod.Path)
+func buildModuleNodes(ctx context.Context, mod *Module) error {
+	rootNode, err := buildNode(mod.Path, mod.options)
 	if err != nil {
 		return err
 	}
 	mod.Nodes["/"] = rootNode
+	mod.progress.update(func(e *ProgressEvent) {
+		e.Folders += 1
+	})
 	rootFileCount := rootNode.FileCount()
 	if rootFileCount > 0 {
 		mod.Stats.PackageCount += 1
@@ -102,11 +166,17 @@ func buildModuleNodes(mod *Module) error {
 		return joinPath("", folder)
 	}))
 	for q.NotEmpty() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		folder, _ := q.Dequeue()
-		node, err := buildNode(mod.Path + folder)
+		node, err := buildNode(mod.Path+folder, mod.options)
 		if err != nil {
 			return err
 		}
+		mod.progress.update(func(e *ProgressEvent) {
+			e.Folders += 1
+		})
 		nodeFileCount := node.FileCount()
 		if nodeFileCount > 0 {
 			mod.Nodes[folder] = node
@@ -117,11 +187,20 @@ func buildModuleNodes(mod *Module) error {
 			q.Enqueue(joinPath(folder, subFolder))
 		}
 	}
+	mod.progress.update(func(e *ProgressEvent) {
+		e.TotalPackages = mod.Stats.PackageCount
+		e.TotalFiles = mod.Stats.FileCount
+	})
+	mod.options.Logger.Debug("folders discovered",
+		"folders", len(mod.Nodes),
+		"packages", mod.Stats.PackageCount,
+		"files", mod.Stats.FileCount,
+	)
 	return nil
 }
 
 // Build Node for given folder path, get subfolders and .go files
-func buildNode(path string) (*Node, error) {
+func buildNode(path string, options *Options) (*Node, error) {
 	entries, err := os.ReadDir(path)
 	if err != nil {
 		return nil, err
@@ -129,9 +208,14 @@ func buildNode(path string) (*Node, error) {
 	node := newNode()
 	for _, e := range entries {
 		name := e.Name()
-		if e.IsDir() && isPublicFolder(name) {
-			node.Folders = append(node.Folders, name)
+		if e.IsDir() {
+			if isPublicFolder(name) && !slices.Contains(options.IgnoreFolders, name) {
+				node.Folders = append(node.Folders, name)
+			}
 		} else if endsWith(name, ".go") {
+			if options.SkipTests && endsWith(name, "_test.go") {
+				continue
+			}
 			node.Files = append(node.Files, name)
 		}
 	}
@@ -159,8 +243,11 @@ func isDirectDependency(line string) bool {
 	return !endsWith(line, "// indirect")
 }
 
-// Check if directory path doesn't start with dot, underscore, dash
+// Check if directory path doesn't start with dot, underscore, dash, and is not testdata (ignored by go tool)
 func isPublicFolder(name string) bool {
+	if name == "testdata" {
+		return false
+	}
 	prefixes := []string{".", "_", "-"}
 	return list.All(prefixes, func(prefix string) bool {
 		return !startsWith(name, prefix)
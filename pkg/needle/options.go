@@ -0,0 +1,179 @@
+package needle
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+// Analysis options
+type Options struct {
+	SkipTests       bool          // skip _test.go files
+	IgnoreFolders   []string      // folder names to skip, on top of .hidden, _private, -dash folders
+	Workers         int           // max number of files analyzed at a time (default: GOMAXPROCS)
+	Timeout         time.Duration // max analysis duration (default: 0 = no timeout)
+	Progress        ProgressFunc  // progress callback (default: nil = no progress reports)
+	Logger          *slog.Logger  // structured logger (default: discard logs)
+	Analyzers       []Analyzer    // custom metrics analyzers, on top of registered ones
+	CoverProfile    string        // Go cover profile path (default: blank = no coverage)
+	ModCache        string        // Go module cache folder (default: $GOMODCACHE, or $GOPATH/pkg/mod)
+	AnalyzeExternal bool          // analyze direct external dependencies from the module cache
+	SkipModuleGraph bool          // skip the external module graph (and licenses, external analysis)
+	SkipLicenses    bool          // skip license detection of external dependencies
+	SkipCallGraph   bool          // skip the call graph
+}
+
+// Functional option for Analyze
+type Option func(*Options)
+
+// Create Options from default values and given options
+func newOptions(opts ...Option) *Options {
+	options := &Options{
+		SkipTests:       false,
+		IgnoreFolders:   make([]string, 0),
+		Workers:         runtime.GOMAXPROCS(0),
+		Timeout:         0,
+		Progress:        nil,
+		Logger:          slog.New(slog.DiscardHandler),
+		Analyzers:       make([]Analyzer, 0),
+		CoverProfile:    "",
+		ModCache:        defaultModCache(),
+		AnalyzeExternal: false,
+		SkipModuleGraph: false,
+		SkipLicenses:    false,
+		SkipCallGraph:   false,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// Option: skip _test.go files
+func WithoutTests() Option {
+	return func(options *Options) {
+		options.SkipTests = true
+	}
+}
+
+// Option: skip folders with given names
+func WithIgnoredFolders(names ...string) Option {
+	return func(options *Options) {
+		options.IgnoreFolders = append(options.IgnoreFolders, names...)
+	}
+}
+
+// Option: max number of files analyzed at a time (ignored if < 1)
+func WithWorkers(workers int) Option {
+	return func(options *Options) {
+		if workers > 0 {
+			options.Workers = workers
+		}
+	}
+}
+
+// Option: max analysis duration, cancels analysis after timeout
+func WithTimeout(timeout time.Duration) Option {
+	return func(options *Options) {
+		options.Timeout = timeout
+	}
+}
+
+// Option: progress callback, called on every progress update
+func WithProgress(fn ProgressFunc) Option {
+	return func(options *Options) {
+		options.Progress = fn
+	}
+}
+
+// Option: structured logger for analysis events (ignored if nil)
+func WithLogger(logger *slog.Logger) Option {
+	return func(options *Options) {
+		if logger != nil {
+			options.Logger = logger
+		}
+	}
+}
+
+// Option: add custom metrics analyzers
+func WithAnalyzers(analyzers ...Analyzer) Option {
+	return func(options *Options) {
+		options.Analyzers = append(options.Analyzers, analyzers...)
+	}
+}
+
+// Option: Go module cache folder, for go.mod files of external modules
+func WithModCache(path string) Option {
+	return func(options *Options) {
+		options.ModCache = path
+	}
+}
+
+// Option: analyze direct external dependencies from their source in the module cache
+func WithExternalAnalysis() Option {
+	return func(options *Options) {
+		options.AnalyzeExternal = true
+	}
+}
+
+// Option: skip the external module graph from the module cache;
+// also skips license detection and external analysis, which need it
+func WithoutModuleGraph() Option {
+	return func(options *Options) {
+		options.SkipModuleGraph = true
+	}
+}
+
+// Option: skip license detection of external dependencies
+func WithoutLicenses() Option {
+	return func(options *Options) {
+		options.SkipLicenses = true
+	}
+}
+
+// Option: skip the call graph (callers / callees of functions)
+func WithoutCallGraph() Option {
+	return func(options *Options) {
+		options.SkipCallGraph = true
+	}
+}
+
+// Check if analysis stage is skipped by the options
+func (o *Options) skipStage(stage Stage) bool {
+	switch stage {
+	case STAGE_MODULES:
+		return o.SkipModuleGraph
+	case STAGE_LICENSES:
+		return o.SkipModuleGraph || o.SkipLicenses
+	case STAGE_EXTERNAL:
+		return o.SkipModuleGraph
+	case STAGE_CALLS:
+		return o.SkipCallGraph
+	}
+	return false
+}
+
+// Get default Go module cache folder: $GOMODCACHE, $GOPATH/pkg/mod, or ~/go/pkg/mod
+func defaultModCache() string {
+	if modCache := os.Getenv("GOMODCACHE"); modCache != "" {
+		return modCache
+	}
+	goPath := filepath.SplitList(os.Getenv("GOPATH"))
+	if len(goPath) > 0 && goPath[0] != "" {
+		return filepath.Join(goPath[0], "pkg", "mod")
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(homeDir, "go", "pkg", "mod")
+}
+
+// Option: attribute statement coverage from a Go cover profile (e.g. go test -coverprofile)
+func WithCoverProfile(path string) Option {
+	return func(options *Options) {
+		options.CoverProfile = path
+	}
+}
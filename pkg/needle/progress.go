@@ -0,0 +1,82 @@
+package needle
+
+import (
+	"sync"
+	"time"
+)
+
+type Stage string
+
+const (
+	STAGE_GOMOD    Stage = "GoMod"
+	STAGE_MODULES  Stage = "Modules"
+	STAGE_LICENSES Stage = "Licenses"
+	STAGE_FOLDERS  Stage = "Folders"
+	STAGE_PACKAGES Stage = "Packages"
+	STAGE_CHECK    Stage = "TypeCheck"
+	STAGE_ERRORS   Stage = "Errors"
+	STAGE_COVERAGE Stage = "Coverage"
+	STAGE_TYPES    Stage = "Types"
+	STAGE_LEVELS   Stage = "Levels"
+	STAGE_LAYOUT   Stage = "Layout"
+	STAGE_DEADCODE Stage = "DeadCode"
+	STAGE_CALLS    Stage = "Calls"
+	STAGE_EXTERNAL Stage = "External"
+	STAGE_EXTRA    Stage = "Extra"
+	STAGE_DONE     Stage = "Done"
+)
+
+// Analysis progress snapshot
+type ProgressEvent struct {
+	Stage         Stage         // current analysis stage
+	Folders       int           // folders discovered so far
+	Packages      int           // packages analyzed so far
+	TotalPackages int           // packages to analyze (known after STAGE_FOLDERS)
+	Files         int           // files analyzed so far
+	TotalFiles    int           // files to analyze (known after STAGE_FOLDERS)
+	Elapsed       time.Duration // time since analysis started
+}
+
+// Progress callback, called sequentially (never concurrently) on every progress update
+type ProgressFunc func(ProgressEvent)
+
+// Compute files analyzed per second
+func (e ProgressEvent) FilesPerSecond() float64 {
+	seconds := e.Elapsed.Seconds()
+	if seconds == 0 {
+		return 0
+	}
+	return float64(e.Files) / seconds
+}
+
+// Progress tracker: serializes updates from concurrent tasks
+type tracker struct {
+	mu    sync.Mutex
+	fn    ProgressFunc
+	start time.Time
+	event ProgressEvent
+}
+
+// Create new tracker, starting the clock
+func newTracker(fn ProgressFunc) *tracker {
+	return &tracker{fn: fn, start: time.Now()}
+}
+
+// Apply change to progress event and report it to the callback
+func (t *tracker) update(change func(*ProgressEvent)) {
+	if t.fn == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	change(&t.event)
+	t.event.Elapsed = time.Since(t.start)
+	t.fn(t.event)
+}
+
+// Report new analysis stage
+func (t *tracker) setStage(stage Stage) {
+	t.update(func(e *ProgressEvent) {
+		e.Stage = stage
+	})
+}
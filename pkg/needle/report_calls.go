@@ -0,0 +1,83 @@
+package needle
+
+import (
+	"github.com/roidaradal/fn/ds"
+	"github.com/roidaradal/fn/number"
+)
+
+// Calls tab view
+type callsView struct {
+	CallCount     string
+	FunctionCount string
+	Functions     tableView
+	Packages      tableView
+}
+
+// Add call graph report data
+func addCallsReport(mod *Module, view *ReportData) {
+	calls := mod.CallGraph.Calls
+	view.Calls.CallCount = number.Comma(len(calls))
+
+	// Functions: callers and callees, interface method calls in italics
+	dynamic := ds.NewSet[[2]string]()
+	for _, call := range calls {
+		if call.Dynamic {
+			dynamic.Add([2]string{call.Caller, call.Callee})
+		}
+	}
+	functions := mod.Functions()
+	view.Calls.FunctionCount = number.Comma(len(functions))
+	table := newTable("No functions")
+	table.ID = "calls-functions-table"
+	if len(functions) > 0 {
+		table.Head = append(table.Head, rowView{
+			th("Package"),
+			th("Function"),
+			th("Callers"),
+			th("Callees"),
+			th("Called By"),
+			th("Calls", withTitle("Interface method calls resolved to an implementation in italics")),
+		})
+	}
+	for _, fn := range functions {
+		id := symbolID(fn.Package, fn.Name)
+		callers := make([]textView, len(fn.Callers))
+		for i, caller := range fn.Callers {
+			callers[i] = textView{Text: caller, Italic: dynamic.Has([2]string{caller, id})}
+		}
+		callees := make([]textView, len(fn.Callees))
+		for i, callee := range fn.Callees {
+			callees[i] = textView{Text: callee, Italic: dynamic.Has([2]string{id, callee})}
+		}
+		table.addGroup("", rowView{
+			td(fn.Package),
+			td(fn.Name),
+			td(number.Comma(len(fn.Callers)), withClass(center)),
+			td(number.Comma(len(fn.Callees)), withClass(center)),
+			td("", withSpans(callers)),
+			td("", withSpans(callees)),
+		})
+	}
+	view.Calls.Functions = table
+
+	// Packages: cross-package call counts
+	packageCalls := mod.CallGraph.PackageCalls()
+	table = newTable("No cross-package calls")
+	if len(packageCalls) > 0 {
+		table.Head = append(table.Head, rowView{
+			th("From"),
+			th("To"),
+			th("Call Sites"),
+			th("Functions", withTitle("Distinct functions called")),
+		})
+	}
+	for _, c := range packageCalls {
+		table.addGroup("", rowView{
+			td(c.From),
+			td(c.To),
+			td(number.Comma(c.Sites), withClass(center)),
+			td(number.Comma(c.Functions), withClass(center)),
+		})
+	}
+	view.Calls.Packages = table
+}
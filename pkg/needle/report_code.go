@@ -0,0 +1,342 @@
+package needle
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/roidaradal/fn/dict"
+	"github.com/roidaradal/fn/ds"
+	"github.com/roidaradal/fn/list"
+	"github.com/roidaradal/fn/number"
+)
+
+var lineTypes = []LineType{LINE_CODE, LINE_ERROR, LINE_HEAD, LINE_COMMENT, LINE_SPACE}
+
+// Code tab view
+type codeView struct {
+	Lines     []summaryPart // line types and total
+	Chars     []summaryPart // char types and total
+	Blocks    []blockView
+	Globals   tableView
+	Functions tableView
+	Types     tableView
+	LineTypes tableView
+	CharTypes tableView
+	Errors    tableView
+	Lengths   tableView
+	Longest   tableView
+}
+
+// Code block count: public and private
+type blockView struct {
+	Label   string
+	Count   string
+	Public  string
+	Private string
+}
+
+// Add code report data
+func addCodeReport(mod *Module, view *ReportData) {
+	// Code Header
+	labels := map[LineType]string{
+		LINE_CODE:    "Code",
+		LINE_ERROR:   "Error",
+		LINE_HEAD:    "Head",
+		LINE_COMMENT: "Comment",
+		LINE_SPACE:   "Space",
+	}
+	modLineCount := mod.Stats.LineCount
+	modCharCount := mod.Stats.CharCount
+	for _, lineType := range lineTypes {
+		lineCount := mod.Code.Lines[lineType]
+		charCount := mod.Code.Chars[lineType]
+		view.Code.Lines = append(view.Code.Lines, summaryPart{
+			Label: labels[lineType],
+			Count: number.Comma(lineCount),
+			Share: percentage(lineCount, modLineCount),
+		})
+		view.Code.Chars = append(view.Code.Chars, summaryPart{
+			Label: labels[lineType],
+			Count: number.Comma(charCount),
+			Share: percentage(charCount, modCharCount),
+		})
+	}
+	view.Code.Lines = append(view.Code.Lines, summaryPart{Label: "Total", Count: number.Comma(modLineCount)})
+	view.Code.Chars = append(view.Code.Chars, summaryPart{Label: "Total", Count: number.Comma(modCharCount)})
+
+	members := map[BlockType][2][]CodeType{
+		CODE_GLOBAL: {
+			{PUB_CONST, PUB_VAR},
+			{PRIV_CONST, PRIV_VAR},
+		},
+		CODE_FUNCTION: {
+			{PUB_FUNCTION, PUB_METHOD},
+			{PRIV_FUNCTION, PRIV_METHOD},
+		},
+		CODE_TYPE: {
+			{PUB_STRUCT, PUB_INTERFACE, PUB_ALIAS},
+			{PRIV_STRUCT, PRIV_INTERFACE, PRIV_ALIAS},
+		},
+	}
+	for _, blockType := range []BlockType{CODE_GLOBAL, CODE_FUNCTION, CODE_TYPE} {
+		view.Code.Blocks = append(view.Code.Blocks, blockView{
+			Label:   string(blockType) + "s",
+			Count:   number.Comma(mod.Code.Blocks[blockType]),
+			Public:  number.Comma(list.Sum(list.Translate(members[blockType][0], mod.Code.Types))),
+			Private: number.Comma(list.Sum(list.Translate(members[blockType][1], mod.Code.Types))),
+		})
+	}
+
+	lookup := ds.NewLookupCode(mod.Packages)
+
+	view.Code.LineTypes = newCodeBreakdown(mod, &codeBreakdownConfig{
+		name:       "lines",
+		header:     "Lines",
+		lookup:     lookup,
+		modCount:   mod.Stats.LineCount,
+		modCounter: mod.Code.Lines,
+		pkgCounter: func(pkg *Package) dict.Counter[LineType] {
+			return pkg.LineTypes
+		},
+		countFn: func(pkg *Package) int {
+			return pkg.LineCount
+		},
+	})
+
+	view.Code.CharTypes = newCodeBreakdown(mod, &codeBreakdownConfig{
+		name:       "chars",
+		header:     "Chars",
+		lookup:     lookup,
+		modCount:   mod.Stats.CharCount,
+		modCounter: mod.Code.Chars,
+		pkgCounter: func(pkg *Package) dict.Counter[LineType] {
+			return pkg.CharTypes
+		},
+		countFn: func(pkg *Package) int {
+			return pkg.CharCount
+		},
+	})
+
+	view.Code.Globals = newCodeTable(mod, &codeConfig{
+		name:      "globals",
+		lookup:    lookup,
+		blockType: CODE_GLOBAL,
+		keys:      []CodeType{PUB_CONST, PRIV_CONST, PUB_VAR, PRIV_VAR},
+	})
+
+	view.Code.Functions = newCodeTable(mod, &codeConfig{
+		name:      "functions",
+		lookup:    lookup,
+		blockType: CODE_FUNCTION,
+		keys:      []CodeType{PUB_FUNCTION, PRIV_FUNCTION, PUB_METHOD, PRIV_METHOD},
+	})
+
+	view.Code.Types = newCodeTable(mod, &codeConfig{
+		name:      "types",
+		lookup:    lookup,
+		blockType: CODE_TYPE,
+		keys:      []CodeType{PUB_STRUCT, PRIV_STRUCT, PUB_INTERFACE, PRIV_INTERFACE, PUB_ALIAS, PRIV_ALIAS},
+	})
+
+	view.Code.Errors = newErrorsTable(mod, lookup)
+}
+
+type codeBreakdownConfig struct {
+	name       string
+	header     string
+	lookup     ds.LookupCode[*Package]
+	modCount   int
+	modCounter dict.Counter[LineType]
+	pkgCounter func(*Package) dict.Counter[LineType]
+	countFn    func(*Package) int
+}
+
+// Create new code breakdown table (lines / chars)
+func newCodeBreakdown(mod *Module, cfg *codeBreakdownConfig) tableView {
+	detailsClass := fmt.Sprintf(" hidden code-%s-list", cfg.name)
+	table := newTable("No packages")
+	head := rowView{th("Packages"), th(cfg.header)}
+	for _, lineType := range lineTypes {
+		head = append(head, th(string(lineType), withColspan(2)))
+	}
+	head = append(head, th("", withButton(toggleButton("code", cfg.name, "%"))))
+	table.Head = append(table.Head, head)
+
+	counts := list.Map(mod.Packages, cfg.countFn)
+	pkgEntries := dict.Entries(dict.Zip(mod.PackageNames(), counts))
+	slices.SortFunc(pkgEntries, sortDescCount)
+	for _, e := range pkgEntries {
+		pkgName, pkgCount := e.Tuple()
+		pkg := cfg.lookup[pkgName]
+		counter := cfg.pkgCounter(pkg)
+		row1 := rowView{
+			td(pkgName, withRowspan(2)),
+			td(number.Comma(pkgCount), withClass(centerLocal)),
+		}
+		row2 := rowView{
+			td(percentage(pkgCount, cfg.modCount), withClass(center+detailsClass)),
+		}
+		for _, lineType := range lineTypes {
+			typeCount := counter[lineType]
+			row1 = append(row1, td(number.Comma(typeCount), withClass(center), withColspan(2)))
+			row2 = append(row2,
+				td(percentage(typeCount, pkgCount), withClass(centerLocal+detailsClass)),
+				td(percentage(typeCount, cfg.modCounter[lineType]), withClass(centerGlobal+detailsClass)),
+			)
+		}
+		table.addGroup(pkgName, row1, row2)
+	}
+	// Footer
+	row1 := rowView{
+		td("TOTAL", withClass(center), withRowspan(2)),
+		td(number.Comma(cfg.modCount), withClass(center), withRowspan(2)),
+	}
+	row2 := rowView{}
+	for _, lineType := range lineTypes {
+		typeCount := cfg.modCounter[lineType]
+		row1 = append(row1, td(number.Comma(typeCount), withClass(centerGlobal), withColspan(2)))
+		row2 = append(row2, td("", withSpans([]textView{
+			{Text: percentage(typeCount, cfg.modCount)},
+			{Text: string(lineType), Bold: true},
+		}), withClass(center), withColspan(2)))
+	}
+	table.Foot = append(table.Foot, row1, row2)
+	return table
+}
+
+type codeConfig struct {
+	name      string
+	lookup    ds.LookupCode[*Package]
+	blockType BlockType
+	keys      []CodeType
+}
+
+// Create new code table (globals / functions / types)
+func newCodeTable(mod *Module, cfg *codeConfig) tableView {
+	detailsClass := fmt.Sprintf(" hidden code-%s-list", cfg.name)
+	table := newTable("No packages")
+	activeKeys := make([]CodeType, 0)
+
+	// Header
+	colspan := 2
+	head := rowView{th("Packages"), th(string(cfg.blockType) + "s")}
+	for _, key := range cfg.keys {
+		if mod.Code.Types[key] == 0 {
+			continue
+		}
+		activeKeys = append(activeKeys, key)
+		head = append(head, th(string(key), withColspan(2)))
+		colspan += 2
+	}
+	head = append(head, th("", withButton(toggleButton("code", cfg.name, "%"))))
+	table.Head = append(table.Head, head)
+
+	// Body
+	modCount := mod.Code.Blocks[cfg.blockType]
+	blankPackages := make([]string, 0)
+
+	counts := list.Map(mod.Packages, func(pkg *Package) int {
+		return pkg.Blocks[cfg.blockType]
+	})
+	pkgEntries := dict.Entries(dict.Zip(mod.PackageNames(), counts))
+	slices.SortFunc(pkgEntries, sortDescCount)
+	for _, e := range pkgEntries {
+		pkgName, pkgCount := e.Tuple()
+		if pkgCount == 0 {
+			blankPackages = append(blankPackages, pkgName)
+			continue
+		}
+		pkg := cfg.lookup[pkgName]
+		row1 := rowView{
+			td(pkgName, withRowspan(2)),
+			td(number.Comma(pkgCount), withClass(centerLocal)),
+		}
+		row2 := rowView{
+			td(percentage(pkgCount, modCount), withClass(center+detailsClass)),
+		}
+		for _, key := range activeKeys {
+			count := pkg.Codes[key]
+			row1 = append(row1, td(number.Comma(count), withClass(center), withColspan(2)))
+			row2 = append(row2,
+				td(percentage(count, pkgCount), withClass(centerLocal+detailsClass)),
+				td(percentage(count, mod.Code.Types[key]), withClass(centerGlobal+detailsClass)),
+			)
+		}
+		table.addGroup(pkgName, row1, row2)
+	}
+
+	// Footer
+	row1 := rowView{
+		td("TOTAL", withClass(center), withRowspan(2)),
+		td(number.Comma(modCount), withClass(center), withRowspan(2)),
+	}
+	row2 := rowView{}
+	for _, key := range activeKeys {
+		count := mod.Code.Types[key]
+		row1 = append(row1, td(number.Comma(count), withClass(centerGlobal), withColspan(2)))
+		row2 = append(row2, td("", withSpans([]textView{
+			{Text: percentage(count, modCount)},
+			{Text: string(key), Bold: true},
+		}), withClass(center), withColspan(2)))
+	}
+	table.Foot = append(table.Foot, row1, row2)
+
+	if len(blankPackages) > 0 {
+		table.Foot = append(table.Foot, rowView{td("", withLines([]string{
+			fmt.Sprintf("Packages without %ss: %d", cfg.blockType, len(blankPackages)),
+			strings.Join(blankPackages, ", "),
+		}), withColspan(colspan))})
+	}
+	return table
+}
+
+// Create new error handling table: error checks, returns, and risky patterns per package
+func newErrorsTable(mod *Module, lookup ds.LookupCode[*Package]) tableView {
+	columns := []struct {
+		name  string
+		title string
+		count func(ErrorProfile) int
+	}{
+		{"Checks", "if err != nil, if err == nil, errors.Is / errors.As", func(p ErrorProfile) int { return p.Checks }},
+		{"Wrapped", "return fmt.Errorf(...%w...)", func(p ErrorProfile) int { return p.WrappedReturns }},
+		{"Formatted", "return fmt.Errorf(...) without %w", func(p ErrorProfile) int { return p.FormattedReturns }},
+		{"New", "return errors.New(...)", func(p ErrorProfile) int { return p.NewReturns }},
+		{"Bare", "return err", func(p ErrorProfile) int { return p.BareReturns }},
+		{"%w", "%w usage in fmt.Errorf", func(p ErrorProfile) int { return p.WrapVerbs }},
+		{"Ignored", "_ = f(), x, _ := f(), where _ is an error result", func(p ErrorProfile) int { return p.Ignored }},
+		{"Unchecked", "f() call statement, where package function f returns an error", func(p ErrorProfile) int { return p.Unchecked }},
+		{"Panics", "panic(...) calls", func(p ErrorProfile) int { return p.Panics }},
+	}
+	table := newTable("No packages")
+
+	// Header
+	head := rowView{th("Packages")}
+	for _, col := range columns {
+		head = append(head, th(col.name, withTitle(col.title)))
+	}
+	table.Head = append(table.Head, head)
+
+	// Body
+	counts := list.Map(mod.Packages, func(pkg *Package) int {
+		return pkg.Errors.Checks
+	})
+	pkgEntries := dict.Entries(dict.Zip(mod.PackageNames(), counts))
+	slices.SortFunc(pkgEntries, sortDescCount)
+	for _, e := range pkgEntries {
+		pkg := lookup[e.Key]
+		row := rowView{td(pkg.Name)}
+		for _, col := range columns {
+			row = append(row, td(number.Comma(col.count(pkg.Errors)), withClass(center)))
+		}
+		table.addGroup(pkg.Name, row)
+	}
+
+	// Footer
+	row := rowView{td("TOTAL", withClass(center))}
+	for _, col := range columns {
+		row = append(row, td(number.Comma(col.count(mod.Code.Errors)), withClass(centerGlobal)))
+	}
+	table.Foot = append(table.Foot, row)
+	return table
+}
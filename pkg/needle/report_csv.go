@@ -0,0 +1,250 @@
+package needle
+
+import (
+	"encoding/csv"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/roidaradal/fn/ds"
+	"github.com/roidaradal/fn/str"
+)
+
+// CSV export table
+type CSVTable string
+
+const (
+	CSV_PACKAGES CSVTable = "packages" // one row per package: all package counters
+	CSV_FILES    CSVTable = "files"    // one row per file: all file counters
+	CSV_EDGES    CSVTable = "edges"    // one row per internal dependency edge
+	CSV_EXTERNAL CSVTable = "external" // one row per external dependency
+)
+
+// All CSV export tables
+var CSVTables = []CSVTable{CSV_PACKAGES, CSV_FILES, CSV_EDGES, CSV_EXTERNAL}
+
+var (
+	fileTypes  = []FileType{FILE_CODE, FILE_TEST}
+	blockTypes = []BlockType{CODE_FUNCTION, CODE_TYPE, CODE_GLOBAL}
+	codeTypes  = []CodeType{
+		PUB_FUNCTION, PRIV_FUNCTION, PUB_METHOD, PRIV_METHOD,
+		PUB_STRUCT, PRIV_STRUCT, PUB_INTERFACE, PRIV_INTERFACE, PUB_ALIAS, PRIV_ALIAS,
+		PUB_CONST, PRIV_CONST, PUB_VAR, PRIV_VAR,
+	}
+)
+
+// Create CSV export of a table: counter columns are named after the JSON fields,
+// e.g. lineTypes.Codes, codes.PubFunction, extra.Analyzer.metric
+func BuildCSVReport(mod *Module, table CSVTable) (string, error) {
+	var rows [][]string
+	switch table {
+	case CSV_PACKAGES:
+		rows = packagesCSV(mod)
+	case CSV_FILES:
+		rows = filesCSV(mod)
+	case CSV_EDGES:
+		rows = edgesCSV(mod)
+	case CSV_EXTERNAL:
+		rows = externalCSV(mod)
+	default:
+		return "", fmt.Errorf("unknown CSV table %q", table)
+	}
+	var out strings.Builder
+	writer := csv.NewWriter(&out)
+	err := writer.WriteAll(rows)
+	if err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// Packages table: file, line, char, block, code type counts, internal and external deps, level
+func packagesCSV(mod *Module) [][]string {
+	extraColumns := mod.extraColumns(false)
+	header := []string{"package", "importPath", "type", "files", "lineCount", "charCount"}
+	header = append(header, counterColumns("fileTypes", fileTypes)...)
+	header = append(header, counterColumns("fileLines", fileTypes)...)
+	header = append(header, counterColumns("fileChars", fileTypes)...)
+	header = append(header, counterHeader()...)
+	header = append(header, "depsOut", "depsIn", "depsExternal", "level", "functions", "coverage.statements", "coverage.covered")
+	header = append(header, extraHeader(extraColumns)...)
+
+	levelOf := make(map[string]string)
+	for level, subPkgs := range mod.Deps.Levels {
+		for _, subPkg := range subPkgs {
+			levelOf[subPkg] = str.Int(level)
+		}
+	}
+	rows := [][]string{header}
+	for _, pkg := range mod.Packages {
+		node := packageToNodeName(pkg.Name)
+		externalCount := 0
+		for _, isInternal := range pkg.Deps {
+			if !isInternal {
+				externalCount += 1
+			}
+		}
+		row := []string{pkg.Name, mod.ImportPath(pkg), string(pkg.Type), str.Int(pkg.FileCount()), str.Int(pkg.LineCount), str.Int(pkg.CharCount)}
+		row = append(row, counterValues(pkg.FileTypes, fileTypes)...)
+		row = append(row, counterValues(pkg.FileLines, fileTypes)...)
+		row = append(row, counterValues(pkg.FileChars, fileTypes)...)
+		row = append(row, counterValues(pkg.LineTypes, lineTypes)...)
+		row = append(row, counterValues(pkg.CharTypes, lineTypes)...)
+		row = append(row, counterValues(pkg.Blocks, blockTypes)...)
+		row = append(row, counterValues(pkg.Codes, codeTypes)...)
+		row = append(row,
+			str.Int(len(mod.Deps.Of[node])),
+			str.Int(len(mod.Deps.InternalUsers[node])),
+			str.Int(externalCount),
+			levelOf[node], // empty if independent
+			str.Int(len(pkg.Functions)),
+			str.Int(pkg.Coverage.Statements),
+			str.Int(pkg.Coverage.Covered),
+		)
+		row = append(row, extraValues(pkg.Extra, extraColumns)...)
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// Files table: line, char, block, code type counts, internal and external deps
+func filesCSV(mod *Module) [][]string {
+	extraColumns := mod.extraColumns(true)
+	header := []string{"package", "file", "path", "type", "lineCount", "charCount"}
+	header = append(header, counterHeader()...)
+	header = append(header, "depsInternal", "depsExternal", "coverage.statements", "coverage.covered")
+	header = append(header, extraHeader(extraColumns)...)
+	rows := [][]string{header}
+	for _, pkg := range mod.Packages {
+		for _, f := range pkg.Files {
+			internalCount, externalCount := 0, 0
+			for _, isInternal := range f.Deps {
+				if isInternal {
+					internalCount += 1
+				} else {
+					externalCount += 1
+				}
+			}
+			row := []string{pkg.Name, f.Name, packageFilePath(pkg.Name, f.Name), string(f.Type), str.Int(len(f.Lines)), str.Int(f.CharCount)}
+			row = append(row, counterValues(f.LineTypes, lineTypes)...)
+			row = append(row, counterValues(f.CharTypes, lineTypes)...)
+			row = append(row, counterValues(f.Blocks, blockTypes)...)
+			row = append(row, counterValues(f.Codes, codeTypes)...)
+			row = append(row,
+				str.Int(internalCount),
+				str.Int(externalCount),
+				str.Int(f.Coverage.Statements),
+				str.Int(f.Coverage.Covered),
+			)
+			row = append(row, extraValues(f.Extra, extraColumns)...)
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// Edges table: internal dependency edges with coupling strength
+func edgesCSV(mod *Module) [][]string {
+	rows := [][]string{{"from", "to", "symbols", "files", "symbolNames"}}
+	for _, c := range mod.Deps.Coupling {
+		rows = append(rows, []string{c.From, c.To, str.Int(len(c.Symbols)), str.Int(c.Files), strings.Join(c.Symbols, " ")})
+	}
+	return rows
+}
+
+// External table: external dependencies with dependents, and module info for direct dependencies
+func externalCSV(mod *Module) [][]string {
+	modules := make(map[string]*ExternalModule)
+	for _, m := range mod.ModuleGraph.Modules {
+		modules[m.Path] = m
+	}
+	rows := [][]string{{"package", "dependents", "dependentPackages", "version", "direct", "license"}}
+	for _, extPkg := range slices.Sorted(maps.Keys(mod.Deps.ExternalUsers)) {
+		users := mod.Deps.ExternalUsers[extPkg]
+		dependents := make([]string, len(users))
+		for i, user := range users {
+			dependents[i] = nodeToPackageName(user)
+		}
+		var version, direct, license string
+		if m, ok := modules[extPkg]; ok {
+			version, direct = m.Version, fmt.Sprint(m.Direct)
+			if m.License != nil {
+				license = strings.Join(m.License.SPDX, " ")
+			}
+		}
+		rows = append(rows, []string{extPkg, str.Int(len(users)), strings.Join(dependents, " "), version, direct, license})
+	}
+	return rows
+}
+
+// Header of line, char, block, and code type counters
+func counterHeader() []string {
+	header := counterColumns("lineTypes", lineTypes)
+	header = append(header, counterColumns("charTypes", lineTypes)...)
+	header = append(header, counterColumns("blocks", blockTypes)...)
+	header = append(header, counterColumns("codes", codeTypes)...)
+	return header
+}
+
+// Column names of counter keys: prefix.key
+func counterColumns[T ~string](prefix string, keys []T) []string {
+	columns := make([]string, len(keys))
+	for i, key := range keys {
+		columns[i] = fmt.Sprintf("%s.%s", prefix, key)
+	}
+	return columns
+}
+
+// Counter values, in keys order
+func counterValues[T comparable](counter map[T]int, keys []T) []string {
+	values := make([]string, len(keys))
+	for i, key := range keys {
+		values[i] = str.Int(counter[key])
+	}
+	return values
+}
+
+// Analyzer metric columns of packages (and files): [analyzer, metric], sorted
+func (mod Module) extraColumns(withFiles bool) [][2]string {
+	columns := ds.NewSet[[2]string]()
+	add := func(extra map[string]Metrics) {
+		for name, metrics := range extra {
+			for metric := range metrics {
+				columns.Add([2]string{name, metric})
+			}
+		}
+	}
+	for _, pkg := range mod.Packages {
+		add(pkg.Extra)
+		if !withFiles {
+			continue
+		}
+		for _, f := range pkg.Files {
+			add(f.Extra)
+		}
+	}
+	items := columns.Items()
+	slices.SortFunc(items, func(a, b [2]string) int {
+		return strings.Compare(a[0]+"\x00"+a[1], b[0]+"\x00"+b[1])
+	})
+	return items
+}
+
+// Header of analyzer metric columns: extra.analyzer.metric
+func extraHeader(columns [][2]string) []string {
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = fmt.Sprintf("extra.%s.%s", column[0], column[1])
+	}
+	return header
+}
+
+// Analyzer metric values, in columns order
+func extraValues(extra map[string]Metrics, columns [][2]string) []string {
+	values := make([]string, len(columns))
+	for i, column := range columns {
+		values[i] = str.Int(extra[column[0]][column[1]])
+	}
+	return values
+}
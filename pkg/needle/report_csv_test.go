@@ -0,0 +1,137 @@
+package needle
+
+import (
+	"context"
+	"encoding/csv"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestBuildCSVReport(t *testing.T) {
+	counts, err := NewPatternAnalyzer("Counts", map[string]string{"funcs": `^func `})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mod, err := Analyze(context.Background(), "testdata/modules/deps", WithoutModuleGraph(), WithAnalyzers(counts))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		table CSVTable
+		key   []string            // columns that identify a row, joined by spaces
+		want  map[string][]string // key => values of columns
+		cols  []string
+	}{
+		{
+			table: CSV_PACKAGES,
+			key:   []string{"package"},
+			cols:  []string{"type", "files", "lineCount", "fileTypes.Test", "lineTypes.Codes", "codes.PubFunction", "depsOut", "depsIn", "level", "extra.Counts.funcs"},
+			want: map[string][]string{
+				"/": {"Main", "1", "12", "0", "4", "0", "2", "0", "2", "1"},
+				"a": {"Lib", "2", "17", "0", "7", "2", "1", "2", "0", "2"},
+				"b": {"Lib", "3", "36", "2", "11", "4", "1", "2", "1", "4"},
+				"c": {"Lib", "1", "4", "0", "1", "1", "0", "0", "", "1"},
+			},
+		},
+		{
+			table: CSV_FILES,
+			key:   []string{"path"},
+			cols:  []string{"package", "type", "lineCount", "blocks.Function", "depsInternal", "extra.Counts.funcs"},
+			want: map[string][]string{
+				"a/a.go":          {"a", "Code", "9", "1", "1", "1"},
+				"b/b_ext_test.go": {"b", "Test", "13", "1", "1", "1"},
+				"c/c.go":          {"c", "Code", "4", "1", "0", "1"},
+			},
+		},
+		{
+			table: CSV_EDGES,
+			key:   []string{"from", "to"},
+			cols:  []string{"symbols", "files", "symbolNames"},
+			want: map[string][]string{
+				"/ a": {"1", "1", "Run"},
+				"a b": {"3", "2", "Load Save Store"},
+				"b a": {"1", "1", "Run"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.table), func(t *testing.T) {
+			report, err := BuildCSVReport(mod, tt.table)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rows, err := csv.NewReader(strings.NewReader(report)).ReadAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+			header := rows[0]
+			found := 0
+			for _, row := range rows[1:] {
+				values := make([]string, len(tt.key))
+				for i, col := range tt.key {
+					values[i] = row[slices.Index(header, col)]
+				}
+				key := strings.Join(values, " ")
+				want, ok := tt.want[key]
+				if !ok {
+					continue
+				}
+				found++
+				for i, col := range tt.cols {
+					idx := slices.Index(header, col)
+					if idx < 0 {
+						t.Fatalf("missing column %q", col)
+					}
+					if row[idx] != want[i] {
+						t.Errorf("%s %s = %q, want %q", key, col, row[idx], want[i])
+					}
+				}
+			}
+			if found != len(tt.want) {
+				t.Errorf("found %d of %d rows", found, len(tt.want))
+			}
+		})
+	}
+	if _, err := BuildCSVReport(mod, "calls"); err == nil {
+		t.Error("BuildCSVReport(calls) = nil error, want error")
+	}
+}
+
+func TestCSVEscaping(t *testing.T) {
+	mod := newModule()
+	mod.Deps.Coupling = []*Coupling{{From: `say "hi"`, To: "a,b", Symbols: []string{"X", "Y"}, Files: 1}}
+	mod.Deps.ExternalUsers = map[string][]string{"example.com/x": {"/", "/a"}}
+	mod.ModuleGraph.Modules = []*ExternalModule{{
+		Path:    "example.com/x",
+		Version: "v1.2.0",
+		Direct:  true,
+		License: &License{SPDX: []string{"MIT", "Apache-2.0"}},
+	}}
+	tests := []struct {
+		table CSVTable
+		want  [][]string
+	}{
+		{CSV_EDGES, [][]string{
+			{"from", "to", "symbols", "files", "symbolNames"},
+			{`say "hi"`, "a,b", "2", "1", "X Y"},
+		}},
+		{CSV_EXTERNAL, [][]string{
+			{"package", "dependents", "dependentPackages", "version", "direct", "license"},
+			{"example.com/x", "2", "/ a", "v1.2.0", "true", "MIT Apache-2.0"},
+		}},
+	}
+	for _, tt := range tests {
+		report, err := BuildCSVReport(mod, tt.table)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows, err := csv.NewReader(strings.NewReader(report)).ReadAll()
+		if err != nil {
+			t.Fatalf("%s: %v", tt.table, err)
+		}
+		if !slices.EqualFunc(rows, tt.want, slices.Equal) {
+			t.Errorf("%s rows = %q, want %q", tt.table, rows, tt.want)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package needle
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/roidaradal/fn/number"
+)
+
+// Dead Code tab view
+type deadCodeView struct {
+	Count   string
+	Summary tableView
+	Symbols tableView
+}
+
+// Add dead code report data
+func addDeadCodeReport(mod *Module, view *ReportData) {
+	dead := mod.Code.DeadCode
+	view.Dead.Count = number.Comma(dead.Count())
+
+	// Summary: packages sorted by descending dead code count
+	table := newTable("No packages")
+	table.Head = append(table.Head, rowView{
+		th("Package"),
+		th("Unexported", withTitle("Unexported symbols never referenced within their package")),
+		th("Exported", withTitle("Exported symbols of internal/ packages never referenced within the module")),
+	})
+	packages := slices.Clone(mod.Packages)
+	slices.SortFunc(packages, func(a, b *Package) int {
+		return cmp.Or(
+			cmp.Compare(b.DeadCode.Count(), a.DeadCode.Count()),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+	for _, pkg := range packages {
+		table.addGroup("", rowView{
+			td(pkg.Name),
+			td(number.Comma(len(pkg.DeadCode.Unexported)), withClass(center)),
+			td(number.Comma(len(pkg.DeadCode.Exported)), withClass(center)),
+		})
+	}
+	table.Foot = append(table.Foot, rowView{
+		td("TOTAL", withClass(center)),
+		td(number.Comma(len(dead.Unexported)), withClass(centerGlobal)),
+		td(number.Comma(len(dead.Exported)), withClass(centerGlobal)),
+	})
+	view.Dead.Summary = table
+
+	// Unreferenced symbols, sorted by package, file, line
+	symbols := append(slices.Clone(dead.Unexported), dead.Exported...)
+	slices.SortFunc(symbols, compareSymbols)
+	view.Dead.Symbols = newSymbolsTable(symbols, "No unreferenced symbols")
+}
@@ -0,0 +1,231 @@
+package needle
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/roidaradal/fn/dict"
+	"github.com/roidaradal/fn/list"
+	"github.com/roidaradal/fn/number"
+	"github.com/roidaradal/fn/str"
+)
+
+// Dependencies tab view
+type depsView struct {
+	PackageCount     string
+	DependentCount   string
+	IndependentCount string
+	ExternalCount    string
+	Dependent        tableView
+	Independent      tableView
+	External         tableView
+	Coupling         tableView
+	Graph            *dependencyGraphView // nil if no dependent packages
+	Modules          modulesView
+}
+
+// Dependency graph data, drawn by the report script
+type dependencyGraphView struct {
+	Nodes    map[string]graphNode `json:"nodes"`
+	Edges    []graphEdge          `json:"edges"`
+	Coverage map[string]int       `json:"coverage"` // package => statement coverage percentage
+}
+
+// Dependency graph edge: weight is the distinct symbols referenced
+type graphEdge struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Weight    int    `json:"weight"`
+	Highlight bool   `json:"highlight"`
+}
+
+// Add dependency report data
+func addDepsReport(mod *Module, view *ReportData) {
+	deps := &view.Deps
+	deps.PackageCount = number.Comma(mod.Stats.PackageCount)
+
+	// External dependencies
+	externalDepsCount := len(mod.Deps.ExternalUsers)
+	deps.ExternalCount = str.Int(externalDepsCount)
+	deps.External = newTable("No external packages")
+	if externalDepsCount > 0 {
+		entries := dict.Entries(mod.Deps.ExternalUsers)
+		slices.SortFunc(entries, func(a, b dict.Entry[string, []string]) int {
+			// Sort by descending order of dependent counts
+			score1 := cmp.Compare(len(b.Value), len(a.Value))
+			if score1 != 0 {
+				return score1
+			}
+			// Tie-breaker: alphabetical
+			return cmp.Compare(a.Key, b.Key)
+		})
+		modules := make(map[string]*ExternalModule) // module path => direct dependency
+		for _, m := range mod.ModuleGraph.Modules {
+			if m.Direct {
+				modules[m.Path] = m
+			}
+		}
+		deps.External.Head = append(deps.External.Head, rowView{
+			th("Package"),
+			th("License", withTitle("Copyleft and unknown licenses in bold")),
+			th("Weight", withTitle("Lines of code, packages, and external dependencies of the module; click for its report")),
+			th("Dependents"),
+			th("", withButton(toggleButton("deps", "external", "Dependents"))),
+		})
+		for _, entry := range entries {
+			extPkg, users := entry.Tuple()
+			m := modules[extPkg]
+			weight := td("", withClass(right))
+			if m != nil && m.Weight != nil {
+				label := fmt.Sprintf("%s LOC, %s pkgs, %s deps", number.Comma(m.Weight.CodeLines), number.Comma(m.Weight.Packages), number.Comma(m.Weight.External))
+				weight = td(label, withClass(right), withLink(externalReportLink(mod.Name, m.Path)))
+			}
+			deps.External.addGroup("", rowView{
+				td(extPkg),
+				licenseCell(m),
+				weight,
+				th(str.Int(len(users))),
+				td("", withLines(list.Map(users, nodeToPackageName)), withClass("deps-external-list hidden")),
+			})
+		}
+	}
+
+	// Independent packages
+	independentCount := len(mod.Deps.Independent)
+	deps.IndependentCount = str.Int(independentCount)
+	deps.Independent = newTable("No independent packages")
+	for _, name := range mod.Deps.Independent {
+		deps.Independent.addGroup(nodeToPackageName(name), rowView{td(nodeToPackageName(name))})
+	}
+
+	// Dependency packages
+	dependentCount := mod.Stats.PackageCount - independentCount
+	deps.DependentCount = str.Int(dependentCount)
+	deps.Dependent = newTable("No dependent packages")
+	deps.Dependent.ID = "deps-dependent-table"
+	if dependentCount > 0 {
+		deps.Dependent.Head = append(deps.Dependent.Head,
+			rowView{
+				th("Level", withRowspan(2)),
+				th("Package", withRowspan(2)),
+				th("Out", withRowspan(2)),
+				th("In", withRowspan(2)),
+				th("", withButton(toggleButton("deps", "dependent", "")), withColspan(2)),
+			},
+			rowView{th("Dependencies"), th("Dependents")},
+		)
+		levels := dict.Keys(mod.Deps.Levels)
+		slices.Sort(levels)
+		for _, level := range levels {
+			for _, subPkg := range mod.Deps.Levels[level] {
+				outDeps := mod.Deps.Of[subPkg]
+				inDeps := mod.Deps.InternalUsers[subPkg]
+				deps.Dependent.addGroup(nodeToPackageName(subPkg), rowView{
+					td(str.Int(level), withClass(center)),
+					td(nodeToPackageName(subPkg), withClass(left)),
+					td(str.Int(len(outDeps)), withClass(center)),
+					td(str.Int(len(inDeps)), withClass(center)),
+					td("", withLines(list.Map(outDeps, nodeToPackageName)), withClass("deps-dependent-list hidden left")),
+					td("", withLines(list.Map(inDeps, nodeToPackageName)), withClass("deps-dependent-list hidden left")),
+				})
+			}
+		}
+		deps.Graph = newDependencyGraph(mod)
+	}
+	deps.Coupling = newCouplingTable(mod)
+}
+
+// Create dependency graph data: node positions, weighted edges, and coverage heatmap
+func newDependencyGraph(mod *Module) *dependencyGraphView {
+	graph := &dependencyGraphView{
+		Nodes:    dependencyPositions(mod),
+		Edges:    make([]graphEdge, 0),
+		Coverage: make(map[string]int),
+	}
+	weights := make(map[[2]string]int)
+	for _, c := range mod.Deps.Coupling {
+		weights[[2]string{c.From, c.To}] = len(c.Symbols)
+	}
+	highlight := make(map[[2]string]bool)
+	for _, edge := range mod.highlight {
+		highlight[edge] = true
+	}
+	for _, subPkg := range slices.Sorted(maps.Keys(mod.Deps.Of)) {
+		from := nodeToPackageName(subPkg)
+		for _, dependency := range mod.Deps.Of[subPkg] {
+			edge := [2]string{from, nodeToPackageName(dependency)}
+			graph.Edges = append(graph.Edges, graphEdge{
+				From:      edge[0],
+				To:        edge[1],
+				Weight:    weights[edge],
+				Highlight: highlight[edge],
+			})
+		}
+	}
+	if mod.HasCoverage() {
+		for _, pkg := range mod.Packages {
+			if !dict.HasKey(graph.Nodes, pkg.Name) || pkg.Coverage.Statements == 0 {
+				continue
+			}
+			graph.Coverage[pkg.Name] = int(math.Round(pkg.Coverage.Percent()))
+		}
+	}
+	return graph
+}
+
+// Create coupling strength table: internal edges sorted by ascending symbol count
+func newCouplingTable(mod *Module) tableView {
+	table := newTable("No internal dependencies")
+	table.ID = "deps-coupling-table"
+	if len(mod.Deps.Coupling) == 0 {
+		return table
+	}
+	couplings := slices.Clone(mod.Deps.Coupling)
+	slices.SortStableFunc(couplings, func(a, b *Coupling) int {
+		return cmp.Compare(len(a.Symbols), len(b.Symbols))
+	})
+	table.Head = append(table.Head, rowView{
+		th("Package"),
+		th("Dependency"),
+		th("Symbols", withTitle("Distinct symbols of dependency referenced")),
+		th("Files", withTitle("Files that import the dependency")),
+		th("Referenced Symbols"),
+	})
+	for _, c := range couplings {
+		count := td(str.Int(len(c.Symbols)), withClass(center))
+		if len(c.Symbols) == 1 {
+			count = td(str.Int(len(c.Symbols)), withClass(center), withBold(), withTitle("Single symbol: decoupling candidate"))
+		}
+		table.addGroup(c.From, rowView{
+			td(c.From),
+			td(c.To),
+			count,
+			td(str.Int(c.Files), withClass(center)),
+			td(strings.Join(c.Symbols, ", ")),
+		})
+	}
+	return table
+}
+
+// License cell of external module: copyleft and unknown licenses in bold
+func licenseCell(m *ExternalModule) cellView {
+	if m == nil || m.License == nil {
+		return td("", withClass(center))
+	}
+	license := m.License
+	switch {
+	case license.NoSource:
+		return td("?", withClass(center), withBold(), withTitle("Module source not in module cache"))
+	case len(license.Files) == 0:
+		return td("None", withClass(center), withBold(), withTitle("No license file"))
+	}
+	cell := td(strings.Join(license.SPDX, " / "), withClass(center), withTitle(strings.Join(license.Files, ", ")))
+	if license.Copyleft || license.Unknown {
+		withBold()(&cell)
+	}
+	return cell
+}
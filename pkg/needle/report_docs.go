@@ -0,0 +1,105 @@
+package needle
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/roidaradal/fn/lang"
+	"github.com/roidaradal/fn/list"
+	"github.com/roidaradal/fn/number"
+	"github.com/roidaradal/fn/str"
+)
+
+var docTypes = []CodeType{PUB_FUNCTION, PUB_METHOD, PUB_STRUCT, PUB_INTERFACE, PUB_ALIAS, PUB_CONST, PUB_VAR}
+
+// Docs tab view
+type docsView struct {
+	Coverage          string
+	DocumentedCount   string
+	ExportedCount     string
+	UndocumentedCount string
+	Packages          tableView
+	Undocumented      tableView
+}
+
+// Add documentation coverage report data
+func addDocsReport(mod *Module, view *ReportData) {
+	docs := mod.Code.Docs
+	view.Docs.Coverage = fmt.Sprintf("%.0f%%", docs.Coverage())
+	view.Docs.DocumentedCount = number.Comma(docs.DocumentedCount())
+	view.Docs.ExportedCount = number.Comma(docs.ExportedCount())
+	view.Docs.UndocumentedCount = number.Comma(len(docs.Undocumented))
+
+	// Coverage table: packages sorted by ascending coverage
+	activeTypes := list.Filter(docTypes, func(codeType CodeType) bool {
+		return docs.Exported[codeType] > 0
+	})
+	table := newTable("No packages")
+	head := rowView{th("Package"), th("Package Doc"), th("Coverage")}
+	for _, codeType := range activeTypes {
+		head = append(head, th(string(codeType)))
+	}
+	table.Head = append(table.Head, head)
+
+	packages := slices.Clone(mod.Packages)
+	slices.SortFunc(packages, func(a, b *Package) int {
+		score1 := cmp.Compare(a.Docs.Coverage(), b.Docs.Coverage())
+		if score1 != 0 {
+			return score1
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+	for _, pkg := range packages {
+		row := rowView{
+			td(pkg.Name),
+			td(lang.Ternary(pkg.Docs.PackageDocs > 0, "Yes", "No"), withClass(center)),
+			td(fmt.Sprintf("%.0f%%", pkg.Docs.Coverage()), withClass(center)),
+		}
+		for _, codeType := range activeTypes {
+			row = append(row, td(docRatio(pkg.Docs, codeType), withClass(center)))
+		}
+		table.addGroup("", row)
+	}
+	row := rowView{
+		td("TOTAL", withClass(center)),
+		td(fmt.Sprintf("%d / %d", docs.PackageDocs, mod.Stats.PackageCount), withClass(centerGlobal)),
+		td(view.Docs.Coverage, withClass(centerGlobal)),
+	}
+	for _, codeType := range activeTypes {
+		row = append(row, td(docRatio(docs, codeType), withClass(centerGlobal)))
+	}
+	table.Foot = append(table.Foot, row)
+	view.Docs.Packages = table
+
+	// Undocumented exported identifiers, sorted by package, file, line
+	view.Docs.Undocumented = newSymbolsTable(docs.Undocumented, "No undocumented exports")
+}
+
+// Create symbols table: package, file, line, type, name
+func newSymbolsTable(symbols []*Symbol, empty string) tableView {
+	table := newTable(empty)
+	if len(symbols) == 0 {
+		return table
+	}
+	table.Head = append(table.Head, rowView{th("Package"), th("File"), th("Line"), th("Type"), th("Name")})
+	for _, symbol := range symbols {
+		table.addGroup("", rowView{
+			td(symbol.Package),
+			td(symbol.File),
+			td(str.Int(symbol.Line), withClass(right)),
+			td(string(symbol.Type), withClass(center)),
+			td(symbol.Name),
+		})
+	}
+	return table
+}
+
+// Documented / exported count of code type, blank if no exported
+func docRatio(docs DocProfile, codeType CodeType) string {
+	exported := docs.Exported[codeType]
+	if exported == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d / %d", docs.Documented[codeType], exported)
+}
@@ -0,0 +1,94 @@
+package needle
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/roidaradal/fn/dict"
+	"github.com/roidaradal/fn/ds"
+	"github.com/roidaradal/fn/list"
+	"github.com/roidaradal/fn/number"
+)
+
+// Extra tab view: one sub-tab per custom analyzer, no tab if there are none
+type extraView struct {
+	Tabs []extraTabView
+}
+
+// Extra sub-tab: analyzer name and metrics table
+type extraTabView struct {
+	Name  string
+	Table tableView
+}
+
+// Add custom analyzer metrics report data, skipping analyzers without metrics
+func addExtraReport(mod *Module, view *ReportData) {
+	for _, name := range slices.Sorted(maps.Keys(mod.Extra)) {
+		if len(mod.Extra[name]) == 0 {
+			continue
+		}
+		view.Extra.Tabs = append(view.Extra.Tabs, extraTabView{
+			Name:  name,
+			Table: newExtraTable(mod, name),
+		})
+	}
+}
+
+// Create new custom metrics table for given analyzer name:
+// one column per metric, packages sorted by first metric, expandable file rows
+func newExtraTable(mod *Module, name string) tableView {
+	metrics := slices.Sorted(maps.Keys(mod.Extra[name]))
+	detailsClass := fmt.Sprintf(" hidden extra-%s-list", name)
+	table := newTable("No packages")
+
+	// Header
+	head := rowView{th("Package")}
+	for _, metric := range metrics {
+		head = append(head, th(metric))
+	}
+	head = append(head, th("", withButton(toggleButton("extra", name, "Files"))))
+	table.Head = append(table.Head, head)
+
+	// Body
+	firstMetric := metrics[0]
+	counts := list.Map(mod.Packages, func(pkg *Package) int {
+		return pkg.Extra[name][firstMetric]
+	})
+	pkgEntries := dict.Entries(dict.Zip(mod.PackageNames(), counts))
+	slices.SortFunc(pkgEntries, sortDescCount)
+	lookup := ds.NewLookupCode(mod.Packages)
+	for _, e := range pkgEntries {
+		pkg := lookup[e.Key]
+		row := rowView{td(pkg.Name)}
+		for _, metric := range metrics {
+			row = append(row, td(number.Comma(pkg.Extra[name][metric]), withClass(center)))
+		}
+		rows := []rowView{append(row, td(""))}
+
+		for _, file := range pkg.Files {
+			row := rowView{td(file.Name, withClass(right+detailsClass))}
+			for _, metric := range metrics {
+				row = append(row, td(number.Comma(file.Extra[name][metric]), withClass(center+detailsClass)))
+			}
+			rows = append(rows, append(row, td("", withClass(detailsClass))))
+		}
+		table.addGroup("", rows...)
+	}
+
+	// Footer
+	row := rowView{th("TOTAL")}
+	for _, metric := range metrics {
+		row = append(row, th(number.Comma(mod.Extra[name][metric])))
+	}
+	table.Foot = append(table.Foot, append(row, th("")))
+	return table
+}
+
+// Name of first Extra sub-tab, blank if there are none
+func (v extraView) FirstTab() string {
+	if len(v.Tabs) == 0 {
+		return ""
+	}
+	return v.Tabs[0].Name
+}
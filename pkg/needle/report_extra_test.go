@@ -0,0 +1,23 @@
+package needle
+
+import "testing"
+
+func TestAddExtraReport(t *testing.T) {
+	mod := newModule()
+	mod.Packages = append(mod.Packages, &Package{
+		Name:  "/",
+		Files: make([]*File, 0),
+		Extra: map[string]Metrics{"todo": {"todos": 2}},
+	})
+	mod.Extra["empty"] = Metrics{}
+	mod.Extra["todo"] = Metrics{"todos": 2}
+
+	view := &ReportData{}
+	addExtraReport(mod, view)
+	if len(view.Extra.Tabs) != 1 || view.Extra.Tabs[0].Name != "todo" {
+		t.Fatalf("addExtraReport tabs = %v, want [todo]", view.Extra.Tabs)
+	}
+	if got := view.Extra.FirstTab(); got != "todo" {
+		t.Errorf("FirstTab() = %q, want %q", got, "todo")
+	}
+}
@@ -0,0 +1,133 @@
+package needle
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/roidaradal/fn/dict"
+	"github.com/roidaradal/fn/ds"
+	"github.com/roidaradal/fn/list"
+	"github.com/roidaradal/fn/number"
+	"github.com/roidaradal/fn/str"
+)
+
+// Max rows for each of the longest and deepest functions
+const maxFunctionRows = 100
+
+// Add function length and nesting depth report data
+func addFunctionsReport(mod *Module, view *ReportData) {
+	functions := mod.Functions()
+	view.Code.Lengths = newFunctionHistogram(mod, functions)
+
+	table := newTable("No functions")
+	table.ID = "functions-longest"
+	view.Code.Longest = table
+	if len(functions) == 0 {
+		return
+	}
+
+	// Union of longest and deepest functions
+	byLength := slices.Clone(functions)
+	slices.SortStableFunc(byLength, func(a, b *Function) int {
+		return cmp.Compare(b.LineCount, a.LineCount)
+	})
+	byDepth := slices.Clone(functions)
+	slices.SortStableFunc(byDepth, func(a, b *Function) int {
+		return cmp.Compare(b.MaxDepth, a.MaxDepth)
+	})
+	rows := byLength[:min(maxFunctionRows, len(byLength))]
+	for _, fn := range byDepth[:min(maxFunctionRows, len(byDepth))] {
+		if !slices.Contains(rows, fn) {
+			rows = append(rows, fn)
+		}
+	}
+	slices.SortStableFunc(rows, func(a, b *Function) int {
+		return cmp.Compare(b.LineCount, a.LineCount)
+	})
+
+	head := rowView{
+		th("Package"),
+		th("File"),
+		th("Line"),
+		th("Function"),
+		th("Lines"),
+		th("Depth"),
+	}
+	for _, lineType := range lineTypes {
+		head = append(head, th(string(lineType)))
+	}
+	if mod.HasCoverage() {
+		head = append(head, th("Coverage"))
+	}
+	table.Head = append(table.Head, head)
+	for _, fn := range rows {
+		row := rowView{
+			td(fn.Package),
+			td(fn.File),
+			td(str.Int(fn.Line), withClass(right)),
+			td(fn.Name),
+			td(number.Comma(fn.LineCount), withClass(center)),
+			td(str.Int(fn.MaxDepth), withClass(center)),
+		}
+		for _, lineType := range lineTypes {
+			row = append(row, td(number.Comma(fn.Lines[lineType]), withClass(center)))
+		}
+		if mod.HasCoverage() {
+			row = append(row, td(coveragePercent(fn.Coverage), withClass(center)))
+		}
+		table.addGroup(fn.Package, row)
+	}
+	view.Code.Longest = table
+}
+
+// Create function length and depth histogram table, per package
+func newFunctionHistogram(mod *Module, functions []*Function) tableView {
+	table := newTable("No packages")
+	head1 := rowView{
+		th("Package", withRowspan(2)),
+		th("Functions", withRowspan(2)),
+		th("Lines", withColspan(len(LengthBuckets))),
+		th("Depth", withColspan(len(DepthBuckets))),
+	}
+	head2 := rowView{}
+	for _, bucket := range LengthBuckets {
+		head2 = append(head2, th(bucket.String()))
+	}
+	for _, bucket := range DepthBuckets {
+		head2 = append(head2, th(bucket.String()))
+	}
+	table.Head = append(table.Head, head1, head2)
+
+	// Packages sorted by descending function count
+	row := func(name string, fns []*Function, class string) rowView {
+		out := rowView{
+			td(name),
+			td(number.Comma(len(fns)), withClass(class)),
+		}
+		lengths := list.Map(fns, func(fn *Function) int {
+			return fn.LineCount
+		})
+		depths := list.Map(fns, func(fn *Function) int {
+			return fn.MaxDepth
+		})
+		for _, count := range histogram(lengths, LengthBuckets) {
+			out = append(out, td(number.Comma(count), withClass(class)))
+		}
+		for _, count := range histogram(depths, DepthBuckets) {
+			out = append(out, td(number.Comma(count), withClass(class)))
+		}
+		return out
+	}
+	counts := list.Map(mod.Packages, func(pkg *Package) int {
+		return len(pkg.Functions)
+	})
+	pkgEntries := dict.Entries(dict.Zip(mod.PackageNames(), counts))
+	slices.SortFunc(pkgEntries, sortDescCount)
+	lookup := ds.NewLookupCode(mod.Packages)
+	for _, e := range pkgEntries {
+		pkg := lookup[e.Key]
+		table.addGroup(pkg.Name, row(pkg.Name, pkg.Functions, center))
+	}
+	table.Foot = append(table.Foot, row("TOTAL", functions, centerGlobal))
+	return table
+}
@@ -0,0 +1,228 @@
+package needle
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/roidaradal/fn/dict"
+	"github.com/roidaradal/fn/list"
+	"github.com/roidaradal/fn/number"
+)
+
+// Markdown report section
+type MarkdownSection string
+
+const (
+	MD_SUMMARY  MarkdownSection = "summary"  // module stats
+	MD_PACKAGES MarkdownSection = "packages" // top packages by lines
+	MD_CODE     MarkdownSection = "code"     // line types and code blocks
+	MD_LEVELS   MarkdownSection = "levels"   // dependency levels
+	MD_GRAPH    MarkdownSection = "graph"    // Mermaid dependency graph
+)
+
+// All Markdown report sections, in report order
+var MarkdownSections = []MarkdownSection{MD_SUMMARY, MD_PACKAGES, MD_CODE, MD_LEVELS, MD_GRAPH}
+
+// GitHub comment body limit (characters)
+const MARKDOWN_MAX_LENGTH = 65536
+
+// Markdown report options: zero values use defaults
+type MarkdownOptions struct {
+	Sections      []MarkdownSection // sections to include, in report order (default: all)
+	TopPackages   int               // packages listed in the packages section (default: 10)
+	MaxGraphEdges int               // graph is omitted if it has more edges (default: 100)
+	MaxLength     int               // sections that don't fit are omitted (default: MARKDOWN_MAX_LENGTH)
+}
+
+// Create Markdown report: for READMEs and PR comments, with a Mermaid dependency graph
+func BuildMarkdownReport(mod *Module, opts MarkdownOptions) string {
+	if len(opts.Sections) == 0 {
+		opts.Sections = MarkdownSections
+	}
+	if opts.TopPackages <= 0 {
+		opts.TopPackages = 10
+	}
+	if opts.MaxGraphEdges <= 0 {
+		opts.MaxGraphEdges = 100
+	}
+	if opts.MaxLength <= 0 {
+		opts.MaxLength = MARKDOWN_MAX_LENGTH
+	}
+	builders := map[MarkdownSection]func(*Module, MarkdownOptions) []string{
+		MD_SUMMARY:  markdownSummary,
+		MD_PACKAGES: markdownPackages,
+		MD_CODE:     markdownCode,
+		MD_LEVELS:   markdownLevels,
+		MD_GRAPH:    markdownGraph,
+	}
+	report := fmt.Sprintf("## Needle: %s\n", mdCode(mod.Name))
+	omitted := make([]string, 0)
+	for _, section := range MarkdownSections {
+		if !slices.Contains(opts.Sections, section) {
+			continue
+		}
+		text := "\n" + strings.Join(builders[section](mod, opts), "\n") + "\n"
+		// Keep room for the omitted sections note
+		if len(report)+len(text)+100 > opts.MaxLength {
+			omitted = append(omitted, string(section))
+			continue
+		}
+		report += text
+	}
+	if len(omitted) > 0 {
+		report += fmt.Sprintf("\n_Omitted to fit %s characters: %s_\n", number.Comma(opts.MaxLength), strings.Join(omitted, ", "))
+	}
+	return report
+}
+
+// Module summary section
+func markdownSummary(mod *Module, opts MarkdownOptions) []string {
+	stats := mod.Stats
+	direct, indirect := mod.ModuleGraph.Count()
+	out := []string{
+		"### Summary",
+		"| Metric | Count | Details |",
+		"| --- | ---: | --- |",
+		fmt.Sprintf("| Packages | %s | %s lib, %s main |", number.Comma(stats.PackageCount), number.Comma(stats.Packages[PKG_LIB]), number.Comma(stats.Packages[PKG_MAIN])),
+		fmt.Sprintf("| Files | %s | %s code, %s test |", number.Comma(stats.FileCount), number.Comma(stats.Files[FILE_CODE]), number.Comma(stats.Files[FILE_TEST])),
+		fmt.Sprintf("| Lines | %s | %s code, %s test |", number.Comma(stats.LineCount), number.Comma(stats.FileLines[FILE_CODE]), number.Comma(stats.FileLines[FILE_TEST])),
+		fmt.Sprintf("| Characters | %s | %s per line |", number.Comma(stats.CharCount), average(stats.CharCount, stats.LineCount)),
+		fmt.Sprintf("| External modules | %s | %s direct, %s indirect |", number.Comma(direct+indirect), number.Comma(direct), number.Comma(indirect)),
+		fmt.Sprintf("| Doc coverage | %.1f%% | exported identifiers |", mod.Code.Docs.Coverage()),
+	}
+	if mod.HasCoverage() {
+		out = append(out, fmt.Sprintf("| Statement coverage | %s | %s statements |", coveragePercent(mod.Code.Coverage), number.Comma(mod.Code.Coverage.Statements)))
+	}
+	return out
+}
+
+// Top packages by lines section
+func markdownPackages(mod *Module, opts MarkdownOptions) []string {
+	out := []string{
+		"### Top Packages",
+		"| Package | Files | Lines | Share | Code Lines |",
+		"| --- | ---: | ---: | ---: | ---: |",
+	}
+	lineCounts := list.Map(mod.Packages, func(pkg *Package) int {
+		return pkg.LineCount
+	})
+	entries := dict.Entries(dict.Zip(mod.PackageNames(), lineCounts))
+	slices.SortFunc(entries, sortDescCount)
+	lookup := make(map[string]*Package)
+	for _, pkg := range mod.Packages {
+		lookup[pkg.Name] = pkg
+	}
+	for i, e := range entries {
+		if i == opts.TopPackages {
+			out = append(out, fmt.Sprintf("\n_and %d more packages_", len(entries)-i))
+			break
+		}
+		pkgName, lineCount := e.Tuple()
+		pkg := lookup[pkgName]
+		out = append(out, fmt.Sprintf("| %s | %s | %s | %s | %s |",
+			mdCode(pkgName),
+			number.Comma(pkg.FileCount()),
+			number.Comma(lineCount),
+			percentage(lineCount, mod.Stats.LineCount),
+			number.Comma(pkg.LineTypes[LINE_CODE]),
+		))
+	}
+	return out
+}
+
+// Code composition section: line types, code blocks
+func markdownCode(mod *Module, opts MarkdownOptions) []string {
+	out := []string{
+		"### Code Composition",
+		"| Lines | Count | Share |",
+		"| --- | ---: | ---: |",
+	}
+	labels := map[LineType]string{
+		LINE_CODE:    "Code",
+		LINE_ERROR:   "Error",
+		LINE_HEAD:    "Head",
+		LINE_COMMENT: "Comment",
+		LINE_SPACE:   "Space",
+	}
+	for _, lineType := range lineTypes {
+		count := mod.Code.Lines[lineType]
+		out = append(out, fmt.Sprintf("| %s | %s | %s |", labels[lineType], number.Comma(count), percentage(count, mod.Stats.LineCount)))
+	}
+	out = append(out,
+		"",
+		"| Blocks | Count | Public | Private |",
+		"| --- | ---: | ---: | ---: |",
+	)
+	members := map[BlockType][2][]CodeType{
+		CODE_FUNCTION: {{PUB_FUNCTION, PUB_METHOD}, {PRIV_FUNCTION, PRIV_METHOD}},
+		CODE_TYPE:     {{PUB_STRUCT, PUB_INTERFACE, PUB_ALIAS}, {PRIV_STRUCT, PRIV_INTERFACE, PRIV_ALIAS}},
+		CODE_GLOBAL:   {{PUB_CONST, PUB_VAR}, {PRIV_CONST, PRIV_VAR}},
+	}
+	for _, blockType := range []BlockType{CODE_FUNCTION, CODE_TYPE, CODE_GLOBAL} {
+		out = append(out, fmt.Sprintf("| %s | %s | %s | %s |",
+			blockType,
+			number.Comma(mod.Code.Blocks[blockType]),
+			number.Comma(list.Sum(list.Translate(members[blockType][0], mod.Code.Types))),
+			number.Comma(list.Sum(list.Translate(members[blockType][1], mod.Code.Types))),
+		))
+	}
+	return out
+}
+
+// Dependency levels section: level 0 = no internal dependencies
+func markdownLevels(mod *Module, opts MarkdownOptions) []string {
+	out := []string{
+		"### Dependency Levels",
+		"| Level | Packages |",
+		"| ---: | --- |",
+	}
+	levels := dict.Keys(mod.Deps.Levels)
+	slices.Sort(levels)
+	for _, level := range slices.Backward(levels) {
+		names := list.Map(mod.Deps.Levels[level], func(name string) string {
+			return mdCode(nodeToPackageName(name))
+		})
+		out = append(out, fmt.Sprintf("| %d | %s |", level, strings.Join(names, " ")))
+	}
+	if len(mod.Deps.Independent) > 0 {
+		names := list.Map(mod.Deps.Independent, func(name string) string {
+			return mdCode(nodeToPackageName(name))
+		})
+		out = append(out, fmt.Sprintf("| Independent | %s |", strings.Join(names, " ")))
+	}
+	return out
+}
+
+// Mermaid dependency graph section: edges labeled with coupling symbol counts
+func markdownGraph(mod *Module, opts MarkdownOptions) []string {
+	out := []string{"### Dependency Graph"}
+	if len(mod.Deps.Coupling) == 0 {
+		return append(out, "_No internal dependencies_")
+	}
+	if len(mod.Deps.Coupling) > opts.MaxGraphEdges {
+		return append(out, fmt.Sprintf("_Omitted: %d edges, more than %d_", len(mod.Deps.Coupling), opts.MaxGraphEdges))
+	}
+	out = append(out, "```mermaid", "flowchart TD")
+	ids := make(map[string]string) // package name => node ID
+	nodeID := func(pkgName string) string {
+		id, ok := ids[pkgName]
+		if !ok {
+			id = fmt.Sprintf("n%d", len(ids))
+			ids[pkgName] = id
+			label := strings.ReplaceAll(pkgName, "\"", "#quot;")
+			out = append(out, fmt.Sprintf("    %s[\"%s\"]", id, label))
+		}
+		return id
+	}
+	for _, c := range mod.Deps.Coupling {
+		from, to := nodeID(c.From), nodeID(c.To)
+		out = append(out, fmt.Sprintf("    %s -->|%d| %s", from, len(c.Symbols), to))
+	}
+	return append(out, "```")
+}
+
+// Format as Markdown inline code, safe inside tables
+func mdCode(text string) string {
+	return "`" + strings.ReplaceAll(text, "|", "\\|") + "`"
+}
@@ -0,0 +1,109 @@
+package needle
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestBuildMarkdownReport(t *testing.T) {
+	mod, err := Analyze(context.Background(), "testdata/modules/deps", WithoutModuleGraph())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		opts MarkdownOptions
+		want []string // expected report lines, in order
+		skip []string // unexpected report lines
+	}{
+		{
+			name: "sections in report order",
+			opts: MarkdownOptions{Sections: []MarkdownSection{MD_GRAPH, MD_LEVELS}},
+			want: []string{
+				"## Needle: `example.com/deps`",
+				"### Dependency Levels",
+				"| 2 | `/` |",
+				"| 1 | `b` |",
+				"| 0 | `a` |",
+				"| Independent | `c` |",
+				"### Dependency Graph",
+				"```mermaid",
+				"    n0[\"/\"]",
+				"    n1[\"a\"]",
+				"    n0 -->|1| n1",
+				"    n1 -->|3| n2",
+				"```",
+			},
+			skip: []string{"### Summary", "### Top Packages", "### Code Composition"},
+		},
+		{
+			name: "top packages",
+			opts: MarkdownOptions{Sections: []MarkdownSection{MD_PACKAGES}, TopPackages: 2},
+			want: []string{
+				"| `b` | 3 | 36 | 52% | 11 |",
+				"| `a` | 2 | 17 | 25% | 7 |",
+				"_and 2 more packages_",
+			},
+			skip: []string{"| `c` | 1 | 4 | 6% | 1 |"},
+		},
+		{
+			name: "graph edge limit",
+			opts: MarkdownOptions{Sections: []MarkdownSection{MD_GRAPH}, MaxGraphEdges: 3},
+			want: []string{"### Dependency Graph", "_Omitted: 4 edges, more than 3_"},
+			skip: []string{"```mermaid"},
+		},
+		{
+			name: "max length",
+			opts: MarkdownOptions{Sections: []MarkdownSection{MD_SUMMARY, MD_LEVELS, MD_GRAPH}, MaxLength: 550},
+			want: []string{"### Summary", "### Dependency Levels", "_Omitted to fit 550 characters: graph_"},
+			skip: []string{"### Dependency Graph"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := BuildMarkdownReport(mod, tt.opts)
+			if tt.opts.MaxLength > 0 && len(report) > tt.opts.MaxLength {
+				t.Errorf("report length = %d, want at most %d", len(report), tt.opts.MaxLength)
+			}
+			lines := strings.Split(report, "\n")
+			i := 0
+			for _, line := range lines {
+				if i < len(tt.want) && line == tt.want[i] {
+					i++
+				}
+				for _, skip := range tt.skip {
+					if line == skip {
+						t.Errorf("unexpected line %q", line)
+					}
+				}
+			}
+			if i < len(tt.want) {
+				t.Errorf("missing line %q, in report:\n%s", tt.want[i], report)
+			}
+		})
+	}
+}
+
+func TestMarkdownEscaping(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"pkg", "`pkg`"},
+		{"a|b", "`a\\|b`"},
+		{"||", "`\\|\\|`"},
+	}
+	for _, tt := range tests {
+		if got := mdCode(tt.text); got != tt.want {
+			t.Errorf("mdCode(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+
+	mod := &Module{}
+	mod.Deps.Coupling = []*Coupling{{From: `say"hi`, To: "b", Symbols: []string{"X"}}}
+	graph := strings.Join(markdownGraph(mod, MarkdownOptions{MaxGraphEdges: 100}), "\n")
+	if !strings.Contains(graph, `n0["say#quot;hi"]`) {
+		t.Errorf("Mermaid label not escaped:\n%s", graph)
+	}
+}
@@ -0,0 +1,191 @@
+package needle
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/roidaradal/fn/dict"
+	"github.com/roidaradal/fn/ds"
+	"github.com/roidaradal/fn/io"
+	"github.com/roidaradal/fn/list"
+	"github.com/roidaradal/fn/number"
+)
+
+// Create report HTML from the built-in templates, with the light theme
+func BuildReport(mod *Module) (string, error) {
+	return BuildHTMLReport(mod, HTMLOptions{})
+}
+
+// Create report HTML from the built-in templates, overridden by user templates (if any)
+func BuildHTMLReport(mod *Module, opts HTMLOptions) (string, error) {
+	if opts.Theme == "" {
+		opts.Theme = THEME_LIGHT
+	}
+	if !slices.Contains(Themes, opts.Theme) {
+		return "", fmt.Errorf("unknown theme %q", opts.Theme)
+	}
+	templates, err := parseReportTemplates(opts.TemplateDir)
+	if err != nil {
+		return "", err
+	}
+	view := &ReportData{ModuleName: mod.Name, Module: mod, Theme: opts.Theme}
+	// Apply report decorators
+	decorators := []func(*Module, *ReportData){
+		addModReport,
+		addStatsReport,
+		addDepsReport,
+		addModulesReport,
+		addCodeReport,
+		addFunctionsReport,
+		addDocsReport,
+		addTestsReport,
+		addTypesReport,
+		addDeadCodeReport,
+		addCallsReport,
+		addExtraReport,
+	}
+	for _, decorator := range decorators {
+		decorator(mod, view)
+	}
+	var report strings.Builder
+	err = templates.ExecuteTemplate(&report, "report.html", view)
+	if err != nil {
+		return "", err
+	}
+	return report.String(), nil
+}
+
+// Save report to output file (~/.needle/<modName>.<ext>), return the output path
+func SaveReport(mod *Module, report, ext string) (string, error) {
+	path, err := getOutputPath(mod.Name, ext)
+	if err != nil {
+		return "", err
+	}
+	err = io.SaveString(report, path)
+	if err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// Build and save reports of analyzed external modules (see WithExternalAnalysis),
+// next to the module report; return report paths
+func SaveExternalReports(mod *Module, opts HTMLOptions) ([]string, error) {
+	paths := make([]string, 0)
+	for _, m := range mod.ModuleGraph.Modules {
+		if m.Analysis == nil {
+			continue
+		}
+		report, err := BuildHTMLReport(m.Analysis, opts)
+		if err != nil {
+			return nil, err
+		}
+		path, err := SaveReport(m.Analysis, report, "html")
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// Get link from module report to external module report: both saved in the output folder
+func externalReportLink(modName, extModName string) string {
+	link, err := filepath.Rel(filepath.Dir(filepath.FromSlash(modName)), filepath.FromSlash(extModName+".html"))
+	if err != nil {
+		return extModName + ".html"
+	}
+	return filepath.ToSlash(link)
+}
+
+// Build output file path
+func getOutputPath(modName, ext string) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	path := fmt.Sprintf("%s/.needle/%s.%s", filepath.ToSlash(homeDir), modName, ext)
+	err = io.EnsurePathExists(path)
+	if err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// Module tab view
+type modView struct {
+	Summary []summaryRow
+	Files   tableView
+	Lines   tableView
+	Chars   tableView
+}
+
+// Summary table row: total, and its parts
+type summaryRow struct {
+	Label string
+	Total string
+	Parts []summaryPart
+}
+
+// Summary table part: count and share of total
+type summaryPart struct {
+	Label string
+	Count string
+	Share string
+}
+
+// Add module report data
+func addModReport(mod *Module, view *ReportData) {
+	fileCounts := list.Map(mod.Packages, (*Package).FileCount)
+	pkgFileCounts := dict.Entries(dict.Zip(mod.PackageNames(), fileCounts))
+	slices.SortFunc(pkgFileCounts, sortDescCount)
+	lookup := ds.NewLookupCode(mod.Packages)
+	hasTest := mod.Stats.Files[FILE_TEST] > 0
+
+	table := newTable("No packages")
+	head := rowView{th("Package"), th("%"), th("Files")}
+	if hasTest {
+		head = append(head, th("Code | Test", withTitle("Code Files | Test Files")))
+	}
+	head = append(head, th("", withButton(toggleButton("mod", "files", "Files"))))
+	table.Head = append(table.Head, head)
+	for _, e := range pkgFileCounts {
+		pkgName, count := e.Tuple()
+		pkg := lookup[pkgName]
+		node := mod.Nodes[packageToNodeName(pkgName)]
+		row := rowView{
+			td(pkgName),
+			td(percentage(count, mod.Stats.FileCount), withClass(center)),
+			td(number.Comma(count), withClass(center)),
+		}
+		if hasTest {
+			row = append(row, td(fmt.Sprintf("%d | %d", pkg.FileTypes[FILE_CODE], pkg.FileTypes[FILE_TEST]), withClass(center)))
+		}
+		row = append(row, td("", withLines(node.Files), withClass("mod-files-list hidden")))
+		table.addGroup(pkgName, row)
+	}
+	view.Mod.Files = table
+
+	view.Mod.Summary = append(view.Mod.Summary, summaryRow{
+		Label: "Packages",
+		Total: number.Comma(mod.Stats.PackageCount),
+		Parts: []summaryPart{
+			{Label: "Library", Count: number.Comma(mod.Stats.Packages[PKG_LIB])},
+			{Label: "Main", Count: number.Comma(mod.Stats.Packages[PKG_MAIN])},
+		},
+	})
+	fileCount := mod.Stats.FileCount
+	files := summaryRow{Label: "Files", Total: number.Comma(fileCount)}
+	for _, fileType := range []FileType{FILE_CODE, FILE_TEST} {
+		typeCount := mod.Stats.Files[fileType]
+		files.Parts = append(files.Parts, summaryPart{
+			Label: string(fileType),
+			Count: number.Comma(typeCount),
+			Share: percentage(typeCount, fileCount),
+		})
+	}
+	view.Mod.Summary = append(view.Mod.Summary, files)
+}
@@ -0,0 +1,82 @@
+package needle
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/roidaradal/fn/number"
+)
+
+// External module graph view, part of the Dependencies tab
+type modulesView struct {
+	Count         string
+	DirectCount   string
+	IndirectCount string
+	Modules       tableView
+	MajorCount    string
+	Majors        tableView
+	Tree          string
+}
+
+// Add external module graph report data
+func addModulesReport(mod *Module, view *ReportData) {
+	graph := mod.ModuleGraph
+	direct, indirect := graph.Count()
+	modules := &view.Deps.Modules
+	modules.Count = number.Comma(len(graph.Modules))
+	modules.DirectCount = number.Comma(direct)
+	modules.IndirectCount = number.Comma(indirect)
+
+	// Modules: selected version, direct dependencies that require it
+	modules.Modules = newTable("No external modules")
+	if len(graph.Modules) > 0 {
+		modules.Modules.Head = append(modules.Modules.Head, rowView{
+			th("Module"),
+			th("Version", withTitle("Selected version: highest required version")),
+			th("Type"),
+			th("Required By", withTitle("Direct dependencies that (transitively) require the module")),
+			th("Versions", withTitle("Required versions in the module graph, if more than one")),
+		})
+	}
+	for _, m := range graph.Modules {
+		version := td(m.Version)
+		if m.Missing {
+			version = td(m.Version+" ?", withTitle("go.mod not found in module cache"))
+		}
+		var versions []string
+		if len(m.Versions) > 1 {
+			versions = m.Versions
+		}
+		modules.Modules.addGroup("", rowView{
+			td(m.Path),
+			version,
+			td(moduleType(m), withClass(center)),
+			td("", withLines(m.Via)),
+			td("", withLines(versions)),
+		})
+	}
+
+	// Duplicate major versions
+	modules.MajorCount = number.Comma(len(graph.Majors))
+	modules.Majors = newTable("No duplicate major versions")
+	if len(graph.Majors) > 0 {
+		modules.Majors.Head = append(modules.Majors.Head, rowView{th("Module"), th("Major Versions")})
+	}
+	for _, base := range slices.Sorted(maps.Keys(graph.Majors)) {
+		modules.Majors.addGroup("", rowView{
+			td(base),
+			td("", withLines(graph.Majors[base])),
+		})
+	}
+
+	modules.Tree = strings.Join(graph.Tree(mod.Name), "\n")
+}
+
+// Module dependency type: direct or indirect
+func moduleType(m *ExternalModule) string {
+	if m.Direct {
+		return "direct"
+	}
+	return "indirect"
+}
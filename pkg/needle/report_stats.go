@@ -0,0 +1,168 @@
+package needle
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/roidaradal/fn/dict"
+	"github.com/roidaradal/fn/ds"
+	"github.com/roidaradal/fn/list"
+	"github.com/roidaradal/fn/number"
+)
+
+// Add stats report data
+func addStatsReport(mod *Module, view *ReportData) {
+	lookup := ds.NewLookupCode(mod.Packages)
+
+	// Lines
+	fileTypes := []FileType{FILE_CODE, FILE_TEST}
+	fileCount := mod.Stats.FileCount
+	lineCount := mod.Stats.LineCount
+	lines := summaryRow{Label: "Lines", Total: number.Comma(lineCount)}
+	alpf := summaryRow{Label: "AvgLinePerFile", Total: average(lineCount, fileCount)}
+	for _, fileType := range fileTypes {
+		typeCount := mod.Stats.FileLines[fileType]
+		lines.Parts = append(lines.Parts, summaryPart{
+			Label: string(fileType),
+			Count: number.Comma(typeCount),
+			Share: percentage(typeCount, lineCount),
+		})
+		alpf.Parts = append(alpf.Parts, summaryPart{
+			Label: string(fileType),
+			Count: average(typeCount, mod.Stats.Files[fileType]),
+		})
+	}
+	linesHead := rowView{th("Package"), th("%"), th("Lines"), th("ALPF", withTitle("AvgLinePerFile"))}
+	linesDetailSpan := 3
+	if mod.HasCoverage() {
+		linesHead = append(linesHead, th("Coverage", withTitle("Covered statements / total statements of package")))
+		linesDetailSpan = 4
+	}
+	linesHead = append(linesHead, th("", withButton(toggleButton("mod", "lines", "File Lines")), withColspan(linesDetailSpan)))
+	view.Mod.Lines = newStatsTable(mod, &statsConfig{
+		name:     "lines",
+		head:     linesHead,
+		lookup:   lookup,
+		modCount: mod.Stats.LineCount,
+		countFn: func(pkg *Package) int {
+			return pkg.LineCount
+		},
+		fileFn: func(f *File) int {
+			return len(f.Lines)
+		},
+		averageCols: func(pkgCount int, pkg *Package, rowspan int) []cellView {
+			cols := []cellView{
+				td(average(pkgCount, pkg.FileCount()), withClass(center), withRowspan(rowspan)),
+			}
+			if mod.HasCoverage() {
+				cols = append(cols, td(coveragePercent(pkg.Coverage), withClass(center), withRowspan(rowspan)))
+			}
+			return cols
+		},
+		fileCols: func(f *File, detailsClass string) []cellView {
+			if !mod.HasCoverage() {
+				return nil
+			}
+			return []cellView{td(coveragePercent(f.Coverage), withClass(center+detailsClass))}
+		},
+	})
+
+	// Characters
+	charCount := mod.Stats.CharCount
+	chars := summaryRow{Label: "Characters", Total: number.Comma(charCount)}
+	acpf := summaryRow{Label: "AvgCharPerFile", Total: average(charCount, fileCount)}
+	acpl := summaryRow{Label: "AvgCharPerLine", Total: average(charCount, lineCount)}
+	for _, fileType := range fileTypes {
+		typeCount := mod.Stats.FileChars[fileType]
+		chars.Parts = append(chars.Parts, summaryPart{
+			Label: string(fileType),
+			Count: number.Comma(typeCount),
+			Share: percentage(typeCount, charCount),
+		})
+		acpf.Parts = append(acpf.Parts, summaryPart{
+			Label: string(fileType),
+			Count: average(typeCount, mod.Stats.Files[fileType]),
+		})
+		acpl.Parts = append(acpl.Parts, summaryPart{
+			Label: string(fileType),
+			Count: average(typeCount, mod.Stats.FileLines[fileType]),
+		})
+	}
+	view.Mod.Chars = newStatsTable(mod, &statsConfig{
+		name: "chars",
+		head: rowView{
+			th("Package"), th("%"), th("Chars"),
+			th("ACPF", withTitle("AvgCharPerFile")),
+			th("ACPL", withTitle("AvgCharPerLine")),
+			th("", withButton(toggleButton("mod", "chars", "File Chars")), withColspan(3)),
+		},
+		lookup:   lookup,
+		modCount: mod.Stats.CharCount,
+		countFn: func(pkg *Package) int {
+			return pkg.CharCount
+		},
+		fileFn: func(f *File) int {
+			return f.CharCount
+		},
+		averageCols: func(pkgCount int, pkg *Package, rowspan int) []cellView {
+			return []cellView{
+				td(average(pkgCount, pkg.FileCount()), withClass(center), withRowspan(rowspan)),
+				td(average(pkgCount, pkg.LineCount), withClass(center), withRowspan(rowspan)),
+			}
+		},
+	})
+
+	view.Mod.Summary = append(view.Mod.Summary, lines, chars, alpf, acpf, acpl)
+}
+
+type statsConfig struct {
+	name        string
+	head        rowView
+	lookup      ds.LookupCode[*Package]
+	modCount    int
+	countFn     func(*Package) int
+	fileFn      func(*File) int
+	averageCols func(int, *Package, int) []cellView
+	fileCols    func(*File, string) []cellView // optional file detail columns
+}
+
+// Create new stats table (lines / char)
+func newStatsTable(mod *Module, cfg *statsConfig) tableView {
+	detailsClass := fmt.Sprintf(" hidden mod-%s-list", cfg.name)
+	table := newTable("No packages")
+	table.Head = append(table.Head, cfg.head)
+	counts := list.Map(mod.Packages, cfg.countFn)
+	pkgEntries := dict.Entries(dict.Zip(mod.PackageNames(), counts))
+	slices.SortFunc(pkgEntries, sortDescCount)
+	for _, e := range pkgEntries {
+		pkgName, pkgCount := e.Tuple()
+		pkg := cfg.lookup[pkgName]
+		rowspan := 1 + pkg.FileCount()
+		row := rowView{
+			td(pkgName, withRowspan(rowspan)),
+			td(percentage(pkgCount, cfg.modCount), withClass(center), withRowspan(rowspan)),
+			td(number.Comma(pkgCount), withClass(center), withRowspan(rowspan)),
+		}
+		row = append(row, cfg.averageCols(pkgCount, pkg, rowspan)...)
+		rows := []rowView{row}
+
+		fileCounts := list.Map(pkg.Files, cfg.fileFn)
+		fileEntries := dict.Entries(dict.Zip(pkg.FileNames(), fileCounts))
+		slices.SortFunc(fileEntries, sortDescCount)
+		fileLookup := ds.NewLookupCode(pkg.Files)
+		for _, e2 := range fileEntries {
+			fileName, fileCount := e2.Tuple()
+			row := rowView{
+				td(number.Comma(fileCount), withClass(right+detailsClass)),
+				td(percentage(fileCount, pkgCount), withClass(center+detailsClass)),
+				td(fileName, withClass(detailsClass)),
+			}
+			if cfg.fileCols != nil {
+				row = append(row, cfg.fileCols(fileLookup[fileName], detailsClass)...)
+			}
+			rows = append(rows, row)
+		}
+		table.addGroup(pkgName, rows...)
+	}
+	return table
+}
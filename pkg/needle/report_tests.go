@@ -0,0 +1,108 @@
+package needle
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/roidaradal/fn/lang"
+	"github.com/roidaradal/fn/number"
+	"github.com/roidaradal/fn/str"
+)
+
+// Tests tab view
+type testsView struct {
+	Ratio                string
+	TestedCount          string
+	TestableCount        string
+	UntestedCount        string
+	TestFuncCount        string
+	UntestedPackageCount string
+	Summary              tableView
+	UntestedPackages     tableView
+	Untested             tableView
+	Mapping              tableView
+}
+
+// Add test-to-code mapping report data
+func addTestsReport(mod *Module, view *ReportData) {
+	tests := mod.Code.Tests
+	view.Tests.Ratio = fmt.Sprintf("%.0f%%", tests.Coverage())
+	view.Tests.TestedCount = number.Comma(tests.Tested)
+	view.Tests.TestableCount = number.Comma(tests.Exported)
+	view.Tests.UntestedCount = number.Comma(len(tests.Untested))
+	view.Tests.TestFuncCount = number.Comma(tests.TestCount())
+
+	// Summary table: packages sorted by ascending tested ratio
+	table := newTable("No packages")
+	head := rowView{th("Package"), th("Internal Files"), th("External Files")}
+	for _, kind := range testKinds {
+		head = append(head, th(string(kind)))
+	}
+	head = append(head, th("Tested"), th("Coverage"))
+	table.Head = append(table.Head, head)
+	row := func(name string, p TestProfile, class string) rowView {
+		out := rowView{
+			td(name, withClass(lang.Ternary(class == centerGlobal, center, ""))),
+			td(number.Comma(p.InternalFiles), withClass(class)),
+			td(number.Comma(p.ExternalFiles), withClass(class)),
+		}
+		for _, kind := range testKinds {
+			out = append(out, td(number.Comma(p.Kinds[kind]), withClass(class)))
+		}
+		return append(out,
+			td(fmt.Sprintf("%d / %d", p.Tested, p.Exported), withClass(class)),
+			td(fmt.Sprintf("%.0f%%", p.Coverage()), withClass(class)),
+		)
+	}
+	packages := slices.Clone(mod.Packages)
+	slices.SortFunc(packages, func(a, b *Package) int {
+		return cmp.Or(
+			cmp.Compare(a.Tests.Coverage(), b.Tests.Coverage()),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+	for _, pkg := range packages {
+		table.addGroup("", row(pkg.Name, pkg.Tests, center))
+	}
+	table.Foot = append(table.Foot, row("TOTAL", tests, centerGlobal))
+	view.Tests.Summary = table
+
+	// Packages without test functions
+	untestedPackages := mod.UntestedPackages()
+	view.Tests.UntestedPackageCount = number.Comma(len(untestedPackages))
+	view.Tests.UntestedPackages = newTable("No untested packages")
+	for _, name := range untestedPackages {
+		view.Tests.UntestedPackages.addGroup("", rowView{td(name)})
+	}
+
+	// Exported functions and methods without tests
+	view.Tests.Untested = newSymbolsTable(tests.Untested, "No untested exported functions")
+
+	// Test functions and their likely targets
+	mapping := newTable("No test functions")
+	if tests.TestCount() > 0 {
+		mapping.Head = append(mapping.Head, rowView{
+			th("Package"),
+			th("File"),
+			th("Line"),
+			th("Kind"),
+			th("Test"),
+			th("Package Clause"),
+			th("Targets"),
+		})
+	}
+	for _, test := range tests.Tests {
+		mapping.addGroup("", rowView{
+			td(test.Package),
+			td(test.File),
+			td(str.Int(test.Line), withClass(right)),
+			td(string(test.Kind), withClass(center)),
+			td(test.Name),
+			td(lang.Ternary(test.External, "External", "Internal"), withClass(center)),
+			td(strings.Join(test.Targets, ", ")),
+		})
+	}
+	view.Tests.Mapping = mapping
+}
@@ -0,0 +1,273 @@
+package needle
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/roidaradal/fn/dict"
+	"github.com/roidaradal/fn/ds"
+	"github.com/roidaradal/fn/list"
+	"github.com/roidaradal/fn/number"
+	"github.com/roidaradal/fn/str"
+)
+
+// Text report options
+type TextOptions struct {
+	Color bool // ANSI colors for titles and table headers
+	Width int  // max line width: wider table columns and lines are truncated (0 = no limit)
+}
+
+// ANSI escape codes
+const (
+	ansiReset = "\033[0m"
+	ansiBold  = "\033[1m"
+	ansiDim   = "\033[2m"
+	ansiCyan  = "\033[36m"
+)
+
+// Terminal text table: left-aligned first column, right-aligned counts
+type textTable struct {
+	header []string
+	rows   [][]string
+	left   map[int]bool // other left-aligned columns
+}
+
+// Create text report: summary, stats, code, and deps sections as aligned tables,
+// with a tree of internal dependencies
+func BuildTextReport(mod *Module, opts TextOptions) string {
+	out := make([]string, 0)
+	title := func(text string) {
+		out = append(out, "", opts.colorize(text, ansiBold+ansiCyan))
+	}
+	table := func(t textTable) {
+		out = append(out, t.render(opts)...)
+	}
+	lookup := ds.NewLookupCode(mod.Packages)
+
+	// Summary
+	out = append(out, opts.colorize(mod.Name, ansiBold))
+	stats := mod.Stats
+	table(textTable{
+		header: []string{"", "Count", "Details"},
+		left:   map[int]bool{2: true},
+		rows: [][]string{
+			{"Packages", number.Comma(stats.PackageCount), fmt.Sprintf("%s lib, %s main", number.Comma(stats.Packages[PKG_LIB]), number.Comma(stats.Packages[PKG_MAIN]))},
+			{"Files", number.Comma(stats.FileCount), fmt.Sprintf("%s code, %s test", number.Comma(stats.Files[FILE_CODE]), number.Comma(stats.Files[FILE_TEST]))},
+			{"Lines", number.Comma(stats.LineCount), fmt.Sprintf("%s per file", average(stats.LineCount, stats.FileCount))},
+			{"Characters", number.Comma(stats.CharCount), fmt.Sprintf("%s per line", average(stats.CharCount, stats.LineCount))},
+		},
+	})
+
+	// Stats: packages by lines
+	title("Stats")
+	lineCounts := list.Map(mod.Packages, func(pkg *Package) int {
+		return pkg.LineCount
+	})
+	pkgEntries := dict.Entries(dict.Zip(mod.PackageNames(), lineCounts))
+	slices.SortFunc(pkgEntries, sortDescCount)
+	statsTable := textTable{header: []string{"Package", "Files", "Lines", "Share", "Lines/File", "Chars", "Chars/Line"}}
+	for _, e := range pkgEntries {
+		pkg := lookup[e.Key]
+		statsTable.rows = append(statsTable.rows, []string{
+			pkg.Name,
+			number.Comma(pkg.FileCount()),
+			number.Comma(pkg.LineCount),
+			percentage(pkg.LineCount, stats.LineCount),
+			average(pkg.LineCount, pkg.FileCount()),
+			number.Comma(pkg.CharCount),
+			average(pkg.CharCount, pkg.LineCount),
+		})
+	}
+	table(statsTable)
+
+	// Code: line types per package, code blocks
+	title("Code")
+	codeTable := textTable{header: []string{"Package", "Code", "Error", "Head", "Comment", "Space"}}
+	for _, e := range pkgEntries {
+		pkg := lookup[e.Key]
+		row := []string{pkg.Name}
+		for _, lineType := range lineTypes {
+			row = append(row, number.Comma(pkg.LineTypes[lineType]))
+		}
+		codeTable.rows = append(codeTable.rows, row)
+	}
+	totalRow := []string{"Total"}
+	for _, lineType := range lineTypes {
+		totalRow = append(totalRow, fmt.Sprintf("%s (%s)", number.Comma(mod.Code.Lines[lineType]), percentage(mod.Code.Lines[lineType], stats.LineCount)))
+	}
+	codeTable.rows = append(codeTable.rows, totalRow)
+	table(codeTable)
+	out = append(out, "")
+	blocksTable := textTable{header: []string{"Block", "Count", "Public", "Private"}}
+	members := map[BlockType][2][]CodeType{
+		CODE_FUNCTION: {{PUB_FUNCTION, PUB_METHOD}, {PRIV_FUNCTION, PRIV_METHOD}},
+		CODE_TYPE:     {{PUB_STRUCT, PUB_INTERFACE, PUB_ALIAS}, {PRIV_STRUCT, PRIV_INTERFACE, PRIV_ALIAS}},
+		CODE_GLOBAL:   {{PUB_CONST, PUB_VAR}, {PRIV_CONST, PRIV_VAR}},
+	}
+	for _, blockType := range []BlockType{CODE_FUNCTION, CODE_TYPE, CODE_GLOBAL} {
+		blocksTable.rows = append(blocksTable.rows, []string{
+			string(blockType),
+			number.Comma(mod.Code.Blocks[blockType]),
+			number.Comma(list.Sum(list.Translate(members[blockType][0], mod.Code.Types))),
+			number.Comma(list.Sum(list.Translate(members[blockType][1], mod.Code.Types))),
+		})
+	}
+	table(blocksTable)
+
+	// Deps: external dependencies, dependency levels, tree
+	title("Dependencies")
+	if len(mod.Deps.ExternalUsers) > 0 {
+		externalTable := textTable{header: []string{"External", "Dependents"}}
+		for _, extPkg := range slices.Sorted(maps.Keys(mod.Deps.ExternalUsers)) {
+			externalTable.rows = append(externalTable.rows, []string{extPkg, str.Int(len(mod.Deps.ExternalUsers[extPkg]))})
+		}
+		table(externalTable)
+		out = append(out, "")
+	}
+	levelsTable := textTable{header: []string{"Package", "Level", "Out", "In"}}
+	levels := dict.Keys(mod.Deps.Levels)
+	slices.Sort(levels)
+	for _, level := range slices.Backward(levels) {
+		for _, subPkg := range mod.Deps.Levels[level] {
+			levelsTable.rows = append(levelsTable.rows, []string{
+				nodeToPackageName(subPkg),
+				str.Int(level),
+				str.Int(len(mod.Deps.Of[subPkg])),
+				str.Int(len(mod.Deps.InternalUsers[subPkg])),
+			})
+		}
+	}
+	for _, subPkg := range mod.Deps.Independent {
+		levelsTable.rows = append(levelsTable.rows, []string{nodeToPackageName(subPkg), "-", "0", "0"})
+	}
+	table(levelsTable)
+	out = append(out, "")
+	for _, line := range mod.dependencyTree() {
+		out = append(out, opts.truncate(line, opts.Width))
+	}
+	return strings.Join(out, "\n") + "\n"
+}
+
+// Render internal dependencies as an ASCII tree, from packages without internal users
+// down to level 0: each package's dependencies are listed once, later occurrences are marked with (*)
+func (mod Module) dependencyTree() []string {
+	levelOf := make(map[string]int)
+	for level, subPkgs := range mod.Deps.Levels {
+		for _, subPkg := range subPkgs {
+			levelOf[subPkg] = level
+		}
+	}
+	lines := make([]string, 0)
+	expanded := ds.NewSet[string]()
+	var walk func(subPkgs []string, prefix string, isRoot bool)
+	walk = func(subPkgs []string, prefix string, isRoot bool) {
+		for i, subPkg := range subPkgs {
+			isLast := i == len(subPkgs)-1
+			branch, indent := "|-- ", "|   "
+			if isLast {
+				branch, indent = "`-- ", "    "
+			}
+			if isRoot {
+				branch, indent = "", ""
+			}
+			label := fmt.Sprintf("%s [%d]", nodeToPackageName(subPkg), levelOf[subPkg])
+			deps := slices.Sorted(slices.Values(mod.Deps.Of[subPkg]))
+			if expanded.Has(subPkg) && len(deps) > 0 {
+				lines = append(lines, prefix+branch+label+" (*)")
+				continue
+			}
+			lines = append(lines, prefix+branch+label)
+			expanded.Add(subPkg)
+			walk(deps, prefix+indent, false)
+		}
+	}
+	roots := make([]string, 0)
+	for subPkg := range levelOf {
+		if len(mod.Deps.InternalUsers[subPkg]) == 0 {
+			roots = append(roots, subPkg)
+		}
+	}
+	slices.Sort(roots)
+	walk(roots, "", true)
+	return lines
+}
+
+// Render table lines: columns padded to widest cell, truncated to fit width:
+// first column down to 10 characters, then the widest other columns down to 3 characters
+func (t textTable) render(opts TextOptions) []string {
+	widths := make([]int, len(t.header))
+	for _, row := range append([][]string{t.header}, t.rows...) {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+	const gap = 2
+	if opts.Width > 0 {
+		excess := list.Sum(widths) + gap*(len(widths)-1) - opts.Width
+		if excess > 0 {
+			width := max(widths[0]-excess, min(widths[0], 10))
+			excess -= widths[0] - width
+			widths[0] = width
+		}
+		for excess > 0 {
+			widest := 0
+			for i := 1; i < len(widths); i++ {
+				if widths[i] > widths[widest] || widest == 0 {
+					widest = i
+				}
+			}
+			if widest == 0 || widths[widest] <= 3 {
+				break // too many columns: lines are truncated
+			}
+			widths[widest] -= 1
+			excess -= 1
+		}
+	}
+	format := func(row []string) string {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cell = opts.truncate(cell, widths[i])
+			padding := strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+			if i == 0 || t.left[i] {
+				cells[i] = cell + padding
+			} else {
+				cells[i] = padding + cell
+			}
+		}
+		line := strings.TrimRight(strings.Join(cells, strings.Repeat(" ", gap)), " ")
+		return opts.truncate(line, opts.Width)
+	}
+	lines := []string{opts.colorize(format(t.header), ansiBold)}
+	rule := list.Map(widths, func(width int) string {
+		return strings.Repeat("-", width)
+	})
+	ruleLine := opts.truncate(strings.Join(rule, strings.Repeat(" ", gap)), opts.Width)
+	lines = append(lines, opts.colorize(ruleLine, ansiDim))
+	for _, row := range t.rows {
+		lines = append(lines, format(row))
+	}
+	return lines
+}
+
+// Wrap text in ANSI code, if colors are enabled
+func (opts TextOptions) colorize(text, code string) string {
+	if !opts.Color || text == "" {
+		return text
+	}
+	return code + text + ansiReset
+}
+
+// Truncate text to width with trailing ..., if width is set
+func (opts TextOptions) truncate(text string, width int) string {
+	if width <= 0 || utf8.RuneCountInString(text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	if width <= 3 {
+		return string(runes[:width])
+	}
+	return string(runes[:width-3]) + "..."
+}
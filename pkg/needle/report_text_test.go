@@ -0,0 +1,125 @@
+package needle
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTextTableRender(t *testing.T) {
+	table := textTable{
+		header: []string{"Package", "Lines", "Details"},
+		left:   map[int]bool{2: true},
+		rows: [][]string{
+			{"internal/billing", "1,234", "3 code, 1 test"},
+			{"/", "56", ""},
+		},
+	}
+	tests := []struct {
+		name  string
+		width int
+		want  []string
+	}{
+		{
+			name:  "no width",
+			width: 0,
+			want: []string{
+				"Package           Lines  Details",
+				"----------------  -----  --------------",
+				"internal/billing  1,234  3 code, 1 test",
+				"/                    56",
+			},
+		},
+		{
+			name:  "first column truncated",
+			width: 34,
+			want: []string{
+				"Package      Lines  Details",
+				"-----------  -----  --------------",
+				"internal...  1,234  3 code, 1 test",
+				"/               56",
+			},
+		},
+		{
+			name:  "widest other column truncated",
+			width: 28,
+			want: []string{
+				"Package     Lines  Details",
+				"----------  -----  ---------",
+				"interna...  1,234  3 code...",
+				"/              56",
+			},
+		},
+		{
+			name:  "lines truncated",
+			width: 18,
+			want: []string{
+				"Package     Lin...",
+				"----------  ---...",
+				"interna...  1,2...",
+				"/            56",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := table.render(TextOptions{Width: tt.width})
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("render() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestTextTruncate(t *testing.T) {
+	tests := []struct {
+		text  string
+		width int
+		want  string
+	}{
+		{"package", 0, "package"},
+		{"package", 7, "package"},
+		{"package", 6, "pac..."},
+		{"package", 3, "pac"},
+		{"päckage", 5, "pä..."},
+	}
+	for _, tt := range tests {
+		if got := (TextOptions{}).truncate(tt.text, tt.width); got != tt.want {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tt.text, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestBuildTextReport(t *testing.T) {
+	mod, err := Analyze(context.Background(), "testdata/modules/deps", WithoutModuleGraph())
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := BuildTextReport(mod, TextOptions{Width: 30})
+	if strings.Contains(report, "\033[") {
+		t.Error("report has ANSI codes with colors disabled")
+	}
+	for line := range strings.Lines(report) {
+		line = strings.TrimSuffix(line, "\n")
+		if utf8.RuneCountInString(line) > 30 {
+			t.Errorf("line %q is wider than 30", line)
+		}
+	}
+	tree := []string{
+		"/ [2]",
+		"|-- a [0]",
+		"|   `-- b [1]",
+		"|       `-- a [0] (*)",
+		"`-- b [1] (*)",
+	}
+	if !strings.HasSuffix(report, "\n"+strings.Join(tree, "\n")+"\n") {
+		t.Errorf("report does not end with dependency tree:\n%s", report)
+	}
+
+	colored := BuildTextReport(mod, TextOptions{Color: true})
+	if !strings.HasPrefix(colored, ansiBold+"example.com/deps"+ansiReset) {
+		t.Errorf("colored report title = %q", strings.SplitN(colored, "\n", 2)[0])
+	}
+}
@@ -0,0 +1,202 @@
+package needle
+
+import (
+	"strings"
+
+	"github.com/roidaradal/fn/ds"
+	"github.com/roidaradal/fn/number"
+	"github.com/roidaradal/fn/str"
+)
+
+// Types graph layout
+const (
+	typeColumnWidth = 250
+	typeRowHeight   = 40
+	typeMargin      = 30
+)
+
+// Types tab view
+type typesView struct {
+	InterfaceCount       string
+	ConcreteCount        string
+	LonelyInterfaceCount string
+	Interfaces           tableView
+	Concrete             tableView
+	Graph                *typesGraphView // nil if no implementations or embedded types
+}
+
+// Types graph data, drawn by the report script: edges are [from, to, implements | embeds]
+type typesGraphView struct {
+	Width  int                     `json:"-"`
+	Height int                     `json:"-"`
+	Nodes  map[string]typeNodeView `json:"nodes"`
+	Edges  [][3]string             `json:"edges"`
+}
+
+// Types graph node: position and if type is an interface
+type typeNodeView struct {
+	X     int  `json:"x"`
+	Y     int  `json:"y"`
+	Iface bool `json:"iface"`
+}
+
+// Add type relationships report data
+func addTypesReport(mod *Module, view *ReportData) {
+	interfaces := make([]*NamedType, 0)
+	concrete := make([]*NamedType, 0)
+	for _, t := range mod.TypeGraph.Types {
+		if t.IsInterface() {
+			interfaces = append(interfaces, t)
+		} else {
+			concrete = append(concrete, t)
+		}
+	}
+	lonely := mod.TypeGraph.LonelyInterfaces()
+	view.Types.InterfaceCount = number.Comma(len(interfaces))
+	view.Types.ConcreteCount = number.Comma(len(concrete))
+	view.Types.LonelyInterfaceCount = number.Comma(len(lonely))
+
+	// Interfaces table
+	table := newTable("No interfaces")
+	if len(interfaces) > 0 {
+		table.Head = append(table.Head, rowView{
+			th("Package"),
+			th("File"),
+			th("Line"),
+			th("Interface"),
+			th("Methods"),
+			th("Impls", withTitle("Implementations; *T if only the pointer type implements it")),
+			th("Implementations"),
+		})
+	}
+	for _, t := range interfaces {
+		count := td(number.Comma(len(t.Implementations)), withClass(center))
+		if t.Partial {
+			count = td("?", withClass(center), withTitle("Embeds external interfaces: method set unknown"))
+		} else if t.Constraint {
+			count = td("-", withClass(center), withTitle("Type constraint"))
+		} else if t.Generic {
+			count = td("?", withClass(center), withTitle("Generic interface: implementations need type arguments"))
+		} else if len(t.Implementations) <= 1 {
+			count = td(number.Comma(len(t.Implementations)), withClass(center), withBold())
+		}
+		table.addGroup("", rowView{
+			td(t.Package),
+			td(t.File),
+			td(str.Int(t.Line), withClass(right)),
+			td(t.Name),
+			td(strings.Join(t.Methods, ", ")),
+			count,
+			td("", withLines(t.Implementations)),
+		})
+	}
+	view.Types.Interfaces = table
+
+	// Concrete types table: method sets, embedded types, implemented interfaces
+	table = newTable("No struct or alias types")
+	if len(concrete) > 0 {
+		table.Head = append(table.Head, rowView{
+			th("Package"),
+			th("File"),
+			th("Line"),
+			th("Type"),
+			th("Methods", withTitle("Method set of *T; pointer-only methods marked with *")),
+			th("Embeds"),
+			th("Implements", withTitle("*I if only the pointer type implements it")),
+		})
+	}
+	for _, t := range concrete {
+		pointerMethods := ds.SetFrom(t.PointerMethods)
+		methods := make([]string, len(t.Methods))
+		for i, name := range t.Methods {
+			if pointerMethods.Has(name) {
+				name = "*" + name
+			}
+			methods[i] = name
+		}
+		table.addGroup("", rowView{
+			td(t.Package),
+			td(t.File),
+			td(str.Int(t.Line), withClass(right)),
+			td(t.Name),
+			td(strings.Join(methods, ", ")),
+			td("", withLines(t.Embeds)),
+			td("", withLines(t.Implements)),
+		})
+	}
+	view.Types.Concrete = table
+
+	view.Types.Graph = newTypesGraph(mod)
+}
+
+// Create types graph data: interfaces in the first column, concrete types to the right
+// of the types they embed; implements and embeds edges
+func newTypesGraph(mod *Module) *typesGraphView {
+	lookup := make(map[string]*NamedType)
+	for _, t := range mod.TypeGraph.Types {
+		lookup[t.ID] = t
+	}
+	edges := make([][3]string, 0)
+	linked := ds.NewSet[string]()
+	for _, t := range mod.TypeGraph.Types {
+		for _, iface := range t.Implements {
+			edges = append(edges, [3]string{t.ID, strings.TrimPrefix(iface, "*"), "implements"})
+			linked.AddItems([]string{t.ID, strings.TrimPrefix(iface, "*")})
+		}
+		for _, embedded := range t.Embeds {
+			embedded = strings.TrimPrefix(embedded, "*")
+			if _, ok := lookup[embedded]; ok {
+				edges = append(edges, [3]string{t.ID, embedded, "embeds"})
+				linked.AddItems([]string{t.ID, embedded})
+			}
+		}
+	}
+	if linked.IsEmpty() {
+		return nil
+	}
+
+	// Column: interfaces = 0, concrete types = 1 + embedding depth
+	column := make(map[string]int)
+	var columnOf func(t *NamedType, visiting *ds.Set[string]) int
+	columnOf = func(t *NamedType, visiting *ds.Set[string]) int {
+		if col, ok := column[t.ID]; ok {
+			return col
+		}
+		if t.IsInterface() {
+			return 0
+		}
+		col := 1
+		visiting.Add(t.ID)
+		for _, embedded := range t.Embeds {
+			e, ok := lookup[strings.TrimPrefix(embedded, "*")]
+			if ok && !e.IsInterface() && !visiting.Has(e.ID) {
+				col = max(col, columnOf(e, visiting)+1)
+			}
+		}
+		visiting.Delete(t.ID)
+		column[t.ID] = col
+		return col
+	}
+	rows := make(map[int]int) // column => next row
+	nodes := make(map[string]typeNodeView)
+	numRows, numColumns := 0, 0
+	for _, t := range mod.TypeGraph.Types {
+		if !linked.Has(t.ID) {
+			continue
+		}
+		col := columnOf(t, ds.NewSet[string]())
+		row := rows[col]
+		rows[col] += 1
+		numRows = max(numRows, row+1)
+		numColumns = max(numColumns, col+1)
+		x := typeMargin + col*typeColumnWidth + typeColumnWidth/2
+		y := typeMargin + row*typeRowHeight + typeRowHeight/2
+		nodes[t.ID] = typeNodeView{X: x, Y: y, Iface: t.IsInterface()}
+	}
+	return &typesGraphView{
+		Width:  2*typeMargin + numColumns*typeColumnWidth,
+		Height: 2*typeMargin + numRows*typeRowHeight,
+		Nodes:  nodes,
+		Edges:  edges,
+	}
+}
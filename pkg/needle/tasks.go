@@ -0,0 +1,65 @@
+package needle
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Run concurrent task for each item, with at most limit tasks running at a time.
+// Task takes item as input and outputs (data, error).
+// onReceive processes each output data.
+// Stops scheduling tasks once ctx is cancelled or a task fails.
+func runTasks[T, D any](ctx context.Context, limit int, items []T, task func(context.Context, T) (D, error), onReceive func(D)) error {
+	eg, taskCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(max(limit, 1))
+	dataCh := make(chan D, len(items))
+
+	// Schedule tasks in the background, since eg.Go blocks when limit is reached
+	var finalErr error
+	go func() {
+		for _, item := range items {
+			if taskCtx.Err() != nil {
+				break
+			}
+			eg.Go(func() error {
+				if err := taskCtx.Err(); err != nil {
+					return err
+				}
+				result, err := task(taskCtx, item)
+				if err != nil {
+					return err
+				}
+				dataCh <- result
+				return nil
+			})
+		}
+		finalErr = eg.Wait()
+		if finalErr == nil {
+			// Stopped scheduling because of parent context
+			finalErr = ctx.Err()
+		}
+		close(dataCh)
+	}()
+
+	// Receive from data channel
+	for result := range dataCh {
+		onReceive(result)
+	}
+	return finalErr
+}
+
+// Acquire a worker slot from the module's worker pool, blocks until available or ctx is done
+func (mod *Module) acquireWorker(ctx context.Context) error {
+	select {
+	case mod.workers <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Release a worker slot back to the module's worker pool
+func (mod *Module) releaseWorker() {
+	<-mod.workers
+}
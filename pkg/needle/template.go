@@ -0,0 +1,61 @@
+package needle
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+// HTML report theme
+type Theme string
+
+const (
+	THEME_LIGHT Theme = "light"
+	THEME_DARK  Theme = "dark"
+	THEME_AUTO  Theme = "auto" // follows the browser's color scheme
+)
+
+// All HTML report themes
+var Themes = []Theme{THEME_LIGHT, THEME_DARK, THEME_AUTO}
+
+// HTML report options: zero values use defaults
+type HTMLOptions struct {
+	// Directory of user templates: files override the built-in files of the same name
+	// (report.html, tabs.html, table.html, theme.css, report.css, print.css, report.js),
+	// and templates override the built-in templates they redefine (e.g. tab-code, head, footer)
+	TemplateDir string
+	Theme       Theme // default: THEME_LIGHT
+}
+
+// Parse report templates: built-in templates, overridden by the templates in dir (if set).
+// Parsed on each build, as html/template can't redefine templates after execution
+func parseReportTemplates(dir string) (*template.Template, error) {
+	tmpl, err := template.ParseFS(templateFS, "templates/*")
+	if err != nil {
+		return nil, err
+	}
+	if dir == "" {
+		return tmpl, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, 0)
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		paths = append(paths, filepath.Join(dir, entry.Name()))
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no templates in %s", dir)
+	}
+	return tmpl.ParseFiles(paths...)
+}
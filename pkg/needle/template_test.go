@@ -0,0 +1,204 @@
+package needle
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTableTemplateEscaping(t *testing.T) {
+	templates, err := parseReportTemplates("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	table := newTable("No rows")
+	table.ID = `t"1`
+	table.Head = []rowView{{th("Name", withColspan(2)), th("Lines")}}
+	table.addGroup(`a"b<c>`, rowView{
+		td(`<script>alert("x")</script>`, withClass(left), withTitle(`say "hi" & <bye>`)),
+		td("link", withLink(`javascript:alert(1)`)),
+		td("", withButton(&buttonView{Label: "<b>", Action: "toggle", Args: []any{`'); alert('x`, 2}})),
+	})
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"table ID", `<table class="data-table" id="t&#34;1">`},
+		{"header colspan", `<th colspan="2">Name</th>`},
+		{"package attribute", `<tbody data-package="a&#34;b&lt;c&gt;">`},
+		{"text", `&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt;`},
+		{"class and title", `<td class="left" title="say &#34;hi&#34; &amp; &lt;bye&gt;">`},
+		{"unsafe link", `<a href="#ZgotmplZ">link</a>`},
+		{"button label", `>&lt;b&gt;</button>`},
+		{"button args", `onclick="toggle(&#34;&#39;); alert(&#39;x&#34;,  2 )"`},
+	}
+	var out strings.Builder
+	if err := templates.ExecuteTemplate(&out, "table", table); err != nil {
+		t.Fatal(err)
+	}
+	html := out.String()
+	for _, tt := range tests {
+		if !strings.Contains(html, tt.want) {
+			t.Errorf("%s: missing %s in\n%s", tt.name, tt.want, html)
+		}
+	}
+	if strings.Contains(html, "<script>") {
+		t.Errorf("unescaped script in\n%s", html)
+	}
+
+	out.Reset()
+	if err := templates.ExecuteTemplate(&out, "table", newTable("No <rows>")); err != nil {
+		t.Fatal(err)
+	}
+	if want := `<tbody class="empty"><tr><td>No &lt;rows&gt;</td></tr></tbody>`; !strings.Contains(out.String(), want) {
+		t.Errorf("empty table = %s, want %s", out.String(), want)
+	}
+}
+
+func TestHTMLReportEscaping(t *testing.T) {
+	mod, err := Analyze(context.Background(), "testdata/modules/deps", WithoutModuleGraph())
+	if err != nil {
+		t.Fatal(err)
+	}
+	mod.Name = `example.com/<deps>"`
+	report, err := BuildReport(mod)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(report, "<deps>") {
+		t.Error("report has unescaped module name")
+	}
+	if !strings.Contains(report, `<title>Needle | example.com/&lt;deps&gt;&#34;</title>`) {
+		t.Error("report title not escaped")
+	}
+}
+
+func TestHTMLReportTemplates(t *testing.T) {
+	mod, err := Analyze(context.Background(), "testdata/modules/deps", WithoutModuleGraph())
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	files := map[string]string{
+		"brand.html":  `{{define "title"}}<h1>ACME {{.ModuleName}}</h1>{{end}}{{define "footer"}}<p>{{len .Module.Packages}} packages</p>{{end}}`,
+		"theme.css":   `:root { --background: #ABCDEF; }`,
+		".hidden.swp": `{{define "title"}}ignored{{end}}`,
+	}
+	for name, text := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		name string
+		opts HTMLOptions
+		want []string
+		skip []string
+	}{
+		{
+			name: "built-in",
+			opts: HTMLOptions{},
+			want: []string{`<html data-theme="light">`, `<h1 class="centered">example.com/deps</h1>`, `[data-theme="dark"]`, `<style media="print">`},
+		},
+		{
+			name: "dark theme",
+			opts: HTMLOptions{Theme: THEME_DARK},
+			want: []string{`<html data-theme="dark">`},
+		},
+		{
+			name: "user templates",
+			opts: HTMLOptions{TemplateDir: dir, Theme: THEME_AUTO},
+			want: []string{`<html data-theme="auto">`, `<h1>ACME example.com/deps</h1>`, `<p>4 packages</p>`, `--background: #ABCDEF;`},
+			skip: []string{`<h1 class="centered">`, `[data-theme="dark"]`, "ignored"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := BuildHTMLReport(mod, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(report, want) {
+					t.Errorf("report is missing %s", want)
+				}
+			}
+			for _, skip := range tt.skip {
+				if strings.Contains(report, skip) {
+					t.Errorf("report has %s", skip)
+				}
+			}
+		})
+	}
+
+	errorTests := []struct {
+		name string
+		opts HTMLOptions
+	}{
+		{"unknown theme", HTMLOptions{Theme: "solarized"}},
+		{"missing folder", HTMLOptions{TemplateDir: filepath.Join(dir, "missing")}},
+		{"empty folder", HTMLOptions{TemplateDir: t.TempDir()}},
+	}
+	for _, tt := range errorTests {
+		if _, err := BuildHTMLReport(mod, tt.opts); err == nil {
+			t.Errorf("%s: BuildHTMLReport() = nil error, want error", tt.name)
+		}
+	}
+}
+
+func TestReportScriptSort(t *testing.T) {
+	node, err := exec.LookPath("node")
+	if err != nil {
+		t.Skip("node not found")
+	}
+	mod, err := Analyze(context.Background(), "testdata/modules/deps", WithoutModuleGraph())
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := BuildReport(mod)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "report.html")
+	if err := os.WriteFile(path, []byte(report), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// Line types table: two header rows, each line type spans count and share columns
+	out, err := exec.Command(node, "testdata/js/dom.js", path, "testdata/js/sort.js", "code-lines.0", "Head", "Head", "Packages").CombinedOutput()
+	if err != nil {
+		t.Fatalf("%v: %s", err, out)
+	}
+	type click struct {
+		Header string
+		Column int
+		Rows   []string
+	}
+	var got struct {
+		Clicks []click
+		Hash   string
+	}
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatalf("%v: %s", err, out)
+	}
+	want := []click{
+		{"Head", 6, []string{"b=11", "/=5", "a=4", "c=1"}}, // numbers: descending first
+		{"Head", 6, []string{"c=1", "a=4", "/=5", "b=11"}},
+		{"Packages", 0, []string{"/=/", "a=a", "b=b", "c=c"}}, // text: ascending first
+	}
+	if len(got.Clicks) != len(want) {
+		t.Fatalf("clicks = %+v, want %+v", got.Clicks, want)
+	}
+	for i, c := range got.Clicks {
+		if c.Header != want[i].Header || c.Column != want[i].Column || !slices.Equal(c.Rows, want[i].Rows) {
+			t.Errorf("click %d = %+v, want %+v", i, c, want[i])
+		}
+	}
+	if wantHash := "sort=code-lines.0%3A0%3Aasc"; !strings.HasSuffix(got.Hash, wantHash) {
+		t.Errorf("hash = %q, want suffix %q", got.Hash, wantHash)
+	}
+}
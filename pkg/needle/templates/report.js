@@ -0,0 +1,356 @@
+const nodeRadius = 30;
+const depsGraph = {{.Deps.Graph}};
+const typesGraph = {{.Types.Graph}};
+var currentTab = 'mod';
+var currentSubTab = {
+    'mod'   : 'summary',
+    'code'  : 'summary',
+    'deps'  : 'dependent',
+    'docs'  : 'coverage',
+    'tests' : 'summary',
+    'types' : 'interfaces',
+    'dead'  : 'summary',
+    'calls' : 'functions',
+    'extra' : {{.Extra.FirstTab}},
+};
+var currentView = {
+    'deps-dependent': 'table',
+};
+var isExpanded = {};
+var sortState = {}; // table key => {column, dir}
+var $id = function(id) { return document.getElementById(id) };
+var $class = function(className) { return Array.from(document.getElementsByClassName(className)) };
+var $value = function(id) { const elt = $id(id); return elt ? elt.value.trim() : '' };
+function changeTab(tab) {
+    if(tab == currentTab) {
+        return;
+    }
+    $id('btn-' + currentTab).classList.remove('active');
+    $id('btn-' + tab).classList.add('active');
+    $id('tabs-' + currentTab).classList.add('hidden');
+    $id('tabs-' + tab).classList.remove('hidden');
+    $id(currentTab).classList.add('hidden');
+    $id(tab).classList.remove('hidden');
+    currentTab = tab;
+    saveState();
+}
+function changeSubTab(tab, subTab) {
+    let old = currentSubTab[tab];
+    if(old == subTab) {
+        return;
+    }
+    $id('btn-' + tab + '-' + old).classList.remove('active');
+    $id('btn-' + tab + '-' + subTab).classList.add('active');
+    $id(tab + '-' + old).classList.add('hidden');
+    $id(tab + '-' + subTab).classList.remove('hidden');
+    currentSubTab[tab] = subTab;
+    saveState();
+}
+function toggleList(tab, subTab, name) {
+    let key = tab + '-' + subTab;
+    let expanded = isExpanded[key];
+    if(expanded) {
+        $id('toggle-'+key).innerHTML = 'Show ' + name;
+        $class(key+'-list').forEach(function(elt){
+            elt.classList.add('hidden');
+        });
+    } else {
+        $id('toggle-'+key).innerHTML = 'Hide ' + name;
+        $class(key+'-list').forEach(function(elt){
+            elt.classList.remove('hidden');
+        });
+    }         
+    isExpanded[key] = !expanded; // toggle
+}
+// Table key in the URL hash: table ID, or sub-tab ID and table index
+function tableKey(table) {
+    if(table.id) {
+        return table.id;
+    }
+    const parent = table.closest('div[id]');
+    const tables = Array.from(parent.getElementsByTagName('table'));
+    return parent.id + '.' + tables.indexOf(table);
+}
+// Header cells of the last header row level (with colspan), mapped to their start column
+function headerColumns(table) {
+    const rows = Array.from(table.tHead ? table.tHead.rows : []);
+    const taken = rows.map(function() { return [] });
+    const columns = [];
+    rows.forEach(function(row, r) {
+        let column = 0;
+        Array.from(row.cells).forEach(function(cell) {
+            while(taken[r][column]) {
+                column++;
+            }
+            for(let i = 0; i < cell.rowSpan; i++) {
+                for(let j = 0; j < cell.colSpan; j++) {
+                    if(r+i < rows.length) {
+                        taken[r+i][column+j] = true;
+                    }
+                }
+            }
+            if(r + cell.rowSpan == rows.length) {
+                columns.push({cell: cell, column: column});
+            }
+            column += cell.colSpan;
+        });
+    });
+    return columns;
+}
+// Cell of row that covers the column, counting colspans
+function cellAt(row, column) {
+    let start = 0;
+    for(const cell of Array.from(row.cells)) {
+        if(column < start + cell.colSpan) {
+            return cell;
+        }
+        start += cell.colSpan;
+    }
+    return null;
+}
+// Sort value of a row group: number of its first row's cell at column, or lowercase text
+function sortValue(tbody, column) {
+    const cell = tbody.rows[0] && cellAt(tbody.rows[0], column);
+    if(!cell) {
+        return '';
+    }
+    const text = cell.textContent.trim();
+    const number = parseFloat(text.replace(/,/g, ''));
+    return isNaN(number) ? text.toLowerCase() : number;
+}
+function sortTable(table, column, dir) {
+    const groups = Array.from(table.tBodies).filter(tbody => !tbody.classList.contains('empty'));
+    const sign = dir == 'asc' ? 1 : -1;
+    const compare = function(a, b) {
+        const x = sortValue(a, column), y = sortValue(b, column);
+        if(typeof x != typeof y) {
+            return typeof x == 'number' ? -1 : 1; // numbers before text
+        }
+        return x < y ? -sign : (x > y ? sign : 0);
+    };
+    groups.sort(compare);
+    groups.forEach(tbody => table.insertBefore(tbody, table.tFoot));
+    headerColumns(table).forEach(function(header) {
+        header.cell.classList.remove('sort-asc', 'sort-desc');
+        if(header.column == column) {
+            header.cell.classList.add('sort-' + dir);
+        }
+    });
+    sortState[tableKey(table)] = {column: column, dir: dir};
+}
+// Sort by header column: descending first for numbers, ascending for text; then toggle
+function clickHeader(table, column) {
+    const state = sortState[tableKey(table)];
+    let dir;
+    if(state && state.column == column) {
+        dir = state.dir == 'asc' ? 'desc' : 'asc';
+    } else {
+        const first = Array.from(table.tBodies).find(tbody => !tbody.classList.contains('empty'));
+        dir = first && typeof sortValue(first, column) == 'number' ? 'desc' : 'asc';
+    }
+    sortTable(table, column, dir);
+    saveState();
+}
+function initSorting() {
+    Array.from(document.querySelectorAll('table.data-table')).forEach(function(table) {
+        headerColumns(table).forEach(function(header) {
+            const cell = header.cell;
+            if(cell.getElementsByTagName('button').length > 0 || cell.textContent.trim() == '') {
+                return;
+            }
+            cell.classList.add('sortable');
+            cell.addEventListener('click', function() { clickHeader(table, header.column) });
+        });
+    });
+}
+// Search box: row groups containing the query; package prefix: Module, Code and Dependencies row groups
+function applyFilters() {
+    const query = $value('search').toLowerCase();
+    const prefix = $value('prefix').replace(/^\/+/, '').replace(/\.\.\.$/, '');
+    const filtered = ['mod', 'code', 'deps'].map($id);
+    Array.from(document.querySelectorAll('table.data-table tbody')).forEach(function(tbody) {
+        if(tbody.classList.contains('empty')) {
+            return;
+        }
+        let visible = query == '' || tbody.textContent.toLowerCase().includes(query);
+        const pkg = tbody.dataset.package;
+        if(visible && prefix != '' && pkg && filtered.some(tab => tab && tab.contains(tbody))) {
+            visible = (pkg + '/').startsWith(prefix);
+        }
+        tbody.classList.toggle('filtered', !visible);
+    });
+    saveState();
+}
+// URL hash: tab, sub-tab, search query, package prefix, and table sorts
+function saveState() {
+    const params = new URLSearchParams();
+    params.set('tab', currentTab);
+    params.set('sub', currentSubTab[currentTab]);
+    const query = $value('search');
+    const prefix = $value('prefix');
+    if(query != '') {
+        params.set('q', query);
+    }
+    if(prefix != '') {
+        params.set('prefix', prefix);
+    }
+    for(let key in sortState) {
+        params.append('sort', key + ':' + sortState[key].column + ':' + sortState[key].dir);
+    }
+    history.replaceState(null, '', '#' + params.toString());
+}
+function loadState() {
+    const params = new URLSearchParams(location.hash.slice(1));
+    const tab = params.get('tab');
+    if(tab && $id('btn-' + tab)) {
+        changeTab(tab);
+        const subTab = params.get('sub');
+        if(subTab && $id('btn-' + tab + '-' + subTab)) {
+            changeSubTab(tab, subTab);
+        }
+    }
+    ['search', 'prefix'].forEach(function(id) {
+        if($id(id)) {
+            $id(id).value = params.get(id == 'search' ? 'q' : id) || '';
+        }
+    });
+    const tables = {};
+    Array.from(document.querySelectorAll('table.data-table')).forEach(function(table) {
+        tables[tableKey(table)] = table;
+    });
+    params.getAll('sort').forEach(function(sort) {
+        const parts = sort.split(':');
+        const column = parseInt(parts[1]);
+        if(parts.length == 3 && tables[parts[0]] && !isNaN(column) && (parts[2] == 'asc' || parts[2] == 'desc')) {
+            sortTable(tables[parts[0]], column, parts[2]);
+        }
+    });
+    applyFilters();
+}
+function drawNode(ctx, node, name) {
+    ctx.beginPath();
+    ctx.arc(node.x, node.y, nodeRadius, 0, Math.PI*2);
+    ctx.fillStyle = node.sink ? '#FF0' : '#DDE';
+    const coverage = depsGraph.coverage;
+    if(name in coverage) {
+        // Heatmap: red (0%) to green (100%) statement coverage
+        ctx.fillStyle = 'hsl(' + (coverage[name] * 1.2) + ', 80%, 70%)';
+    }
+    ctx.fill();
+    ctx.strokeStyle = '#333';
+    ctx.stroke();
+    ctx.fillStyle = '#F00';
+    ctx.textAlign = 'center';
+    ctx.textBaseline = 'middle';
+    ctx.fillText(name, node.x, node.y);
+    if(name in coverage) {
+        ctx.fillText(coverage[name] + '%', node.x, node.y + nodeRadius / 2);
+    }
+}
+function drawEdge(ctx, node1, node2, weight, isHighlighted) {
+    const angle = Math.atan2(node2.y-node1.y, node2.x-node1.x);
+    const arrowSize = 10;
+    const x1 = node1.x + Math.cos(angle) * nodeRadius;
+    const y1 = node1.y + Math.sin(angle) * nodeRadius;
+    const x2 = node2.x - Math.cos(angle) * nodeRadius;
+    const y2 = node2.y - Math.sin(angle) * nodeRadius;
+
+    ctx.beginPath();
+    ctx.moveTo(x1,y1); ctx.lineTo(x2,y2);
+    ctx.strokeStyle = isHighlighted ? '#F00' : '#555';
+    // Thickness: distinct symbols referenced
+    ctx.lineWidth = 1 + Math.log2(Math.max(weight || 1, 1));
+    if(isHighlighted) {
+        ctx.lineWidth += 2;
+    }
+    ctx.stroke();
+    ctx.lineWidth = 1;
+
+    ctx.save();
+    ctx.translate(x2, y2); ctx.rotate(angle);
+    ctx.beginPath();
+    ctx.moveTo(0, 0);
+    ctx.lineTo(-arrowSize, -arrowSize / 2);
+    ctx.lineTo(-arrowSize, arrowSize / 2);
+    ctx.closePath();
+    ctx.fillStyle = isHighlighted ? '#F00' : '#555';
+    ctx.fill();
+    ctx.restore();
+}
+function drawGraph(ctx) {
+    const nodes = depsGraph.nodes;
+    depsGraph.edges.forEach(edge => {
+        const node1 = nodes[edge.from];
+        const node2 = nodes[edge.to];
+        if(node1 && node2) {
+            drawEdge(ctx, node1, node2, edge.weight, edge.highlight);
+        }
+    });
+    for(let key in nodes) {
+        drawNode(ctx, nodes[key], key)
+    }
+}
+function drawTypesGraph(ctx) {
+    ctx.font = '12px sans-serif';
+    ctx.textAlign = 'center';
+    ctx.textBaseline = 'middle';
+    const boxWidth = function(name) { return ctx.measureText(name).width + 16 };
+    const typeNodes = typesGraph.nodes;
+    // Edges: implements = solid, embeds = dashed
+    typesGraph.edges.forEach(edge => {
+        const node1 = typeNodes[edge[0]];
+        const node2 = typeNodes[edge[1]];
+        if(!node1 || !node2) {
+            return;
+        }
+        ctx.beginPath();
+        ctx.setLineDash(edge[2] == 'embeds' ? [4, 4] : []);
+        ctx.moveTo(node1.x - boxWidth(edge[0])/2, node1.y);
+        ctx.lineTo(node2.x + boxWidth(edge[1])/2, node2.y);
+        ctx.strokeStyle = edge[2] == 'embeds' ? '#999' : '#555';
+        ctx.stroke();
+    });
+    ctx.setLineDash([]);
+    for(let key in typeNodes) {
+        const node = typeNodes[key];
+        const width = boxWidth(key);
+        ctx.fillStyle = node.iface ? '#DEF' : '#EED';
+        ctx.fillRect(node.x - width/2, node.y - 12, width, 24);
+        ctx.strokeStyle = '#333';
+        ctx.strokeRect(node.x - width/2, node.y - 12, width, 24);
+        ctx.fillStyle = '#000';
+        ctx.fillText(key, node.x, node.y);
+    }
+}
+function toggleDependentView() {
+    if(currentView['deps-dependent'] == 'table') {
+        $id('deps-dependent-graph').classList.remove('hidden');
+        $id('deps-dependent-table').classList.add('hidden');
+        $id('view-deps-dependent').innerHTML = 'Show Table';
+        currentView['deps-dependent'] = 'graph';
+    } else {
+        $id('deps-dependent-graph').classList.add('hidden');
+        $id('deps-dependent-table').classList.remove('hidden');
+        $id('view-deps-dependent').innerHTML = 'Show Graph';
+        currentView['deps-dependent'] = 'table';
+    }
+}
+window.onload = function(){
+    const canvas = $id('deps-dependent-graph');
+    if(depsGraph && canvas) {
+        const ctx = canvas.getContext('2d');
+        ctx.clearRect(0,0, canvas.width, canvas.height);
+        drawGraph(ctx);
+    }
+    const typesCanvas = $id('types-graph-canvas');
+    if(typesGraph && typesCanvas) {
+        drawTypesGraph(typesCanvas.getContext('2d'));
+    }
+    initSorting();
+    loadState();
+    // Show highlighted import chains
+    if(depsGraph && depsGraph.edges.some(edge => edge.highlight)) {
+        changeTab('deps');
+        toggleDependentView();
+    }
+};
@@ -0,0 +1,123 @@
+// Minimal DOM for running the report script in node: node dom.js report.html check.js [args...]
+// Parses the report HTML into elements with the properties the report script uses,
+// runs the report script and its onload handler, then the check script
+const fs = require('fs');
+const html = fs.readFileSync(process.argv[2], 'utf8');
+const voidTags = new Set(['meta', 'link', 'input', 'br', 'img', 'hr']);
+
+function unescape(text) {
+    return text.replace(/&lt;/g, '<').replace(/&gt;/g, '>').replace(/&#39;/g, "'").replace(/&#34;/g, '"').replace(/&amp;/g, '&');
+}
+
+class Element {
+    constructor(tag, attrs, parent) {
+        this.tagName = tag.toUpperCase();
+        this.attrs = attrs;
+        this.children = [];
+        this.parentNode = parent;
+        this.listeners = {};
+        this.value = attrs.value || '';
+        const classes = new Set((attrs['class'] || '').split(/\s+/).filter(c => c));
+        this.classList = {
+            add: (...names) => names.forEach(c => classes.add(c)),
+            remove: (...names) => names.forEach(c => classes.delete(c)),
+            contains: c => classes.has(c),
+            toggle: (c, force) => force ? classes.add(c) : classes.delete(c),
+        };
+        this.dataset = {};
+        for(const key in attrs) {
+            if(key.startsWith('data-')) {
+                this.dataset[key.slice(5)] = attrs[key];
+            }
+        }
+        this.style = {};
+    }
+    get id() { return this.attrs.id || '' }
+    get rowSpan() { return +(this.attrs.rowspan || 1) }
+    get colSpan() { return +(this.attrs.colspan || 1) }
+    get textContent() { return this.children.map(c => typeof c == 'string' ? c : c.textContent).join('') }
+    get elements() { return this.children.filter(c => typeof c != 'string') }
+    get descendants() { return this.elements.flatMap(e => [e, ...e.descendants]) }
+    get tHead() { return this.elements.find(e => e.tagName == 'THEAD') || null }
+    get tFoot() { return this.elements.find(e => e.tagName == 'TFOOT') || null }
+    get tBodies() { return this.elements.filter(e => e.tagName == 'TBODY') }
+    get rows() { return this.elements.filter(e => e.tagName == 'TR') }
+    get cells() { return this.elements.filter(e => e.tagName == 'TD' || e.tagName == 'TH') }
+    set innerHTML(text) { this.children = [text] }
+    getElementsByTagName(tag) { return this.descendants.filter(e => e.tagName == tag.toUpperCase()) }
+    closest(selector) { // only div[id]
+        let e = this;
+        while(e && !(e.tagName == 'DIV' && e.id)) {
+            e = e.parentNode;
+        }
+        return e;
+    }
+    contains(other) {
+        while(other && other !== this) {
+            other = other.parentNode;
+        }
+        return other === this;
+    }
+    insertBefore(node, ref) {
+        const siblings = node.parentNode.children;
+        siblings.splice(siblings.indexOf(node), 1);
+        node.parentNode = this;
+        if(ref) {
+            this.children.splice(this.children.indexOf(ref), 0, node);
+        } else {
+            this.children.push(node);
+        }
+    }
+    addEventListener(type, listener) { this.listeners[type] = listener }
+    click() { this.listeners.click && this.listeners.click() }
+    getContext() { // canvas: no-op drawing
+        return new Proxy({}, {
+            get: (target, key) => key == 'measureText' ? () => ({width: 10}) : (key in target ? target[key] : () => {}),
+            set: (target, key, value) => { target[key] = value; return true },
+        });
+    }
+}
+
+const root = new Element('root', {}, null);
+let current = root;
+const tokens = /<!--[\s\S]*?-->|<(script|style)[^>]*>[\s\S]*?<\/\1>|<\/?([a-zA-Z0-9]+)([^>]*)>|([^<]+)/g;
+for(const m of html.matchAll(tokens)) {
+    if(m[1] || m[0].startsWith('<!')) {
+        continue; // comment, doctype, script, style
+    }
+    if(m[4] !== undefined) {
+        current.children.push(unescape(m[4]));
+        continue;
+    }
+    const tag = m[2].toUpperCase();
+    if(m[0][1] == '/') {
+        while(current.tagName != tag && current.parentNode) {
+            current = current.parentNode;
+        }
+        current = current.parentNode || root;
+        continue;
+    }
+    const attrs = {};
+    m[3].replace(/([\w-]+)(?:="([^"]*)")?/g, (_, key, value) => { attrs[key] = value === undefined ? '' : unescape(value) });
+    const element = new Element(tag, attrs, current);
+    current.children.push(element);
+    if(!voidTags.has(m[2].toLowerCase()) && !m[3].endsWith('/')) {
+        current = element;
+    }
+}
+
+const dataTables = () => root.descendants.filter(e => e.tagName == 'TABLE' && e.classList.contains('data-table'));
+global.document = {
+    getElementById: id => root.descendants.find(e => e.id == id) || null,
+    getElementsByClassName: name => root.descendants.filter(e => e.classList.contains(name)),
+    querySelectorAll: selector => selector == 'table.data-table' ? dataTables() : dataTables().flatMap(t => t.tBodies),
+};
+global.location = {hash: ''};
+global.history = {replaceState: (state, title, hash) => { location.hash = hash }};
+global.window = {};
+
+const scripts = html.match(/<script>[\s\S]*?<\/script>/g);
+const script = scripts[scripts.length - 1].replace(/<\/?script>/g, '');
+const check = fs.readFileSync(process.argv[3], 'utf8');
+global.args = process.argv.slice(4);
+eval(script + ';window.onload();' + check);
@@ -0,0 +1,15 @@
+// Click the headers of a table, print the first cells of its row groups after each click, as JSON:
+// node dom.js report.html sort.js tableKey header...
+const [key, ...headers] = args;
+const table = document.querySelectorAll('table.data-table').find(t => tableKey(t) == key);
+const columns = headerColumns(table);
+const clicks = headers.map(function(label) {
+    const header = columns.find(h => h.cell.textContent.trim() == label);
+    header.cell.click();
+    return {
+        header: label,
+        column: header.column,
+        rows: table.tBodies.map(tbody => tbody.rows[0].cells[0].textContent.trim() + '=' + cellAt(tbody.rows[0], header.column).textContent.trim()),
+    };
+});
+console.log(JSON.stringify({clicks: clicks, hash: location.hash}));
@@ -0,0 +1,5 @@
+module example.com/Old
+
+go 1.16
+
+require example.com/leaf v0.1.0-beta
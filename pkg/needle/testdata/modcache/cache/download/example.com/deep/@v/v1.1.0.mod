@@ -0,0 +1,5 @@
+module example.com/deep
+
+go 1.21
+
+require example.com/hidden v1.1.0
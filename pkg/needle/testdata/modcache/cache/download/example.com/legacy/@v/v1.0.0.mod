@@ -0,0 +1,6 @@
+module example.com/legacy
+
+require (
+	example.com/Old v0.3.0
+	example.com/app v0.1.0
+)
@@ -0,0 +1,5 @@
+module example.com/pruned
+
+go 1.17
+
+require example.com/deep v1.1.0
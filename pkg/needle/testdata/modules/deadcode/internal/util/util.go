@@ -0,0 +1,5 @@
+package util
+
+func Used() {}
+
+func Unused() {}
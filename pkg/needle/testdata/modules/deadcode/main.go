@@ -0,0 +1,58 @@
+package main
+
+import "example.com/deadcode/internal/util"
+
+type saver interface {
+	save()
+}
+
+type closer interface {
+	close()
+	name() string
+}
+
+type fileStore struct{}
+
+func (s *fileStore) flush() {}
+
+func (s *fileStore) save() {}
+
+type memStore struct{}
+
+// Same name as fileStore.flush, never called on a memStore
+func (m *memStore) flush() {}
+
+func (m memStore) String() string {
+	return "mem"
+}
+
+type base struct{}
+
+func (b base) close() {}
+
+type wrapped struct {
+	base
+}
+
+func (w wrapped) name() string {
+	return "wrapped"
+}
+
+var unusedVar = 1
+
+func helper() {}
+
+func testedOnly() {}
+
+func main() {
+	store := &fileStore{}
+	store.flush()
+	var s saver = store
+	_ = s
+	var c closer = wrapped{}
+	_ = c
+	_ = memStore{}
+	unusedVar := 2 // shadows the package-level var
+	_ = unusedVar
+	util.Used()
+}
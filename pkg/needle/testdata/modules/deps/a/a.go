@@ -0,0 +1,8 @@
+package a
+
+import "example.com/deps/b"
+
+func Run() {
+	b.Save(b.Load())
+	b.Save("again")
+}
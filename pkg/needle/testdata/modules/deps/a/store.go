@@ -0,0 +1,7 @@
+package a
+
+import store "example.com/deps/b"
+
+func NewStore() store.Store {
+	return store.Store{}
+}
@@ -0,0 +1,9 @@
+package b
+
+type Store struct{}
+
+func Load() string {
+	return ""
+}
+
+func Save(value string) {}
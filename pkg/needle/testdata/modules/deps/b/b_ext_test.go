@@ -0,0 +1,12 @@
+package b_test
+
+import (
+	"testing"
+
+	"example.com/deps/b"
+)
+
+// External test package importing its own package
+func TestLoad(t *testing.T) {
+	b.Load()
+}
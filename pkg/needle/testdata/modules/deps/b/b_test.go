@@ -0,0 +1,12 @@
+package b
+
+import (
+	"testing"
+
+	"example.com/deps/a"
+)
+
+// Import cycle through a test file: a imports b
+func TestSave(t *testing.T) {
+	a.Run()
+}
@@ -0,0 +1,11 @@
+package main
+
+import (
+	"example.com/deps/a"
+	"example.com/deps/b"
+)
+
+func main() {
+	a.Run()
+	_ = b.Store{}
+}
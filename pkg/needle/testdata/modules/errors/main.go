@@ -0,0 +1,35 @@
+package main
+
+import (
+	"os"
+
+	"example.com/errors/store"
+)
+
+func run() error {
+	return nil
+}
+
+func pair() (int, error) {
+	return 0, nil
+}
+
+func main() {
+	run()          // unchecked: same package
+	_ = run()      // ignored: same package
+	n, _ := pair() // ignored: same package
+	s := store.New()
+	s.Save()        // unchecked: cross-package method
+	_, _ = s.Load() // ignored: cross-package method
+	save := s.Save
+	save()         // unchecked: method value
+	defer s.Save() // deferred: not counted
+	c := &store.Cache{}
+	c.Save()           // no error result
+	os.Remove("x")     // outside the module: unknown
+	_ = os.Remove("x") // ignored: imported function
+	if err := run(); err != nil {
+		return
+	}
+	_ = n
+}
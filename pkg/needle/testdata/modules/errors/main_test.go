@@ -0,0 +1,7 @@
+package main
+
+import "testing"
+
+func TestRun(t *testing.T) {
+	run() // unchecked: in-package test
+}
@@ -0,0 +1,22 @@
+package store
+
+import "errors"
+
+type Store struct{}
+
+type Cache struct{}
+
+func New() *Store {
+	return &Store{}
+}
+
+func (s *Store) Save() error {
+	return errors.New("not saved")
+}
+
+func (s *Store) Load() (string, error) {
+	return "", nil
+}
+
+// Same method name as Store.Save, without an error
+func (c *Cache) Save() {}
@@ -0,0 +1,11 @@
+package store_test
+
+import (
+	"testing"
+
+	"example.com/errors/store"
+)
+
+func TestSave(t *testing.T) {
+	store.New().Save() // unchecked: external test package
+}
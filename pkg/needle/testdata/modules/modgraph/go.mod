@@ -0,0 +1,8 @@
+module example.com/app
+
+go 1.25
+
+require (
+	example.com/legacy v1.0.0
+	example.com/pruned v1.2.0
+)
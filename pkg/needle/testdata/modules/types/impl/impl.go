@@ -0,0 +1,41 @@
+package impl
+
+import (
+	"io"
+
+	"example.com/types/shapes"
+)
+
+type Square struct{}
+
+func (s Square) Area() float64 {
+	return 1
+}
+
+func (s *Square) Name() string {
+	return "square"
+}
+
+type Box[T any] struct {
+	value T
+}
+
+func (b Box[T]) Area() shapes.Float {
+	return 0
+}
+
+func (b Box[T]) Get() T {
+	return b.value
+}
+
+type Circle struct{}
+
+// Same method name as Shape.Area, different signature
+func (c Circle) Area() int {
+	return 0
+}
+
+type Framed struct {
+	*Square
+	io.Writer
+}
@@ -0,0 +1,27 @@
+package shapes
+
+import "io"
+
+type Float = float64
+
+type Shape interface {
+	Area() Float
+}
+
+type Named interface {
+	Shape
+	Name() string
+}
+
+type Getter[T any] interface {
+	Get() T
+}
+
+type Source interface {
+	io.Reader
+	Name() string
+}
+
+type Number interface {
+	~int | ~float64
+}
@@ -0,0 +1,341 @@
+package needle
+
+import (
+	"cmp"
+	"go/ast"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/roidaradal/fn/dict"
+	"github.com/roidaradal/fn/ds"
+	"github.com/roidaradal/fn/number"
+)
+
+type TestKind string
+
+const (
+	TEST_FUNC      TestKind = "Test"
+	TEST_BENCHMARK TestKind = "Benchmark"
+	TEST_FUZZ      TestKind = "Fuzz"
+	TEST_EXAMPLE   TestKind = "Example"
+)
+
+var testKinds = []TestKind{TEST_FUNC, TEST_BENCHMARK, TEST_FUZZ, TEST_EXAMPLE}
+
+// Test, benchmark, fuzz, or example function
+type TestFunc struct {
+	Name     string   `json:"name"`     // test function name
+	Kind     TestKind `json:"kind"`     // Test, Benchmark, Fuzz, Example
+	Package  string   `json:"package"`  // package name
+	File     string   `json:"file"`     // test file name
+	Line     int      `json:"line"`     // line number
+	External bool     `json:"external"` // true if in black-box package foo_test
+	Targets  []string `json:"targets"`  // functions, types, Type.Methods it likely exercises
+}
+
+// Test coverage of package structure
+type TestProfile struct {
+	Kinds         dict.Counter[TestKind] `json:"kinds"`         // test function counts by kind
+	InternalFiles int                    `json:"internalFiles"` // test files in package foo
+	ExternalFiles int                    `json:"externalFiles"` // test files in package foo_test
+	Exported      int                    `json:"exported"`      // exported functions and methods
+	Tested        int                    `json:"tested"`        // exported functions and methods targeted by tests
+	Tests         []*TestFunc            `json:"tests"`         // test functions
+	Untested      []*Symbol              `json:"untested"`      // exported functions and methods without tests
+}
+
+// Create new TestProfile
+func newTestProfile() TestProfile {
+	return TestProfile{
+		Kinds:    make(dict.Counter[TestKind]),
+		Tests:    make([]*TestFunc, 0),
+		Untested: make([]*Symbol, 0),
+	}
+}
+
+// Add other test profile counts and lists
+func (p *TestProfile) add(other TestProfile) {
+	dict.UpdateCounts(p.Kinds, other.Kinds)
+	p.InternalFiles += other.InternalFiles
+	p.ExternalFiles += other.ExternalFiles
+	p.Exported += other.Exported
+	p.Tested += other.Tested
+	p.Tests = append(p.Tests, other.Tests...)
+	p.Untested = append(p.Untested, other.Untested...)
+}
+
+// Total test functions of all kinds
+func (p TestProfile) TestCount() int {
+	return len(p.Tests)
+}
+
+// Percentage of exported functions and methods targeted by tests (100 if none exported)
+func (p TestProfile) Coverage() float64 {
+	if p.Exported == 0 {
+		return 100
+	}
+	return number.Ratio(p.Tested*100, p.Exported)
+}
+
+// Compute package test profile: parse test functions from test files,
+// map them to package functions, types, and methods by name convention and calls
+func (pkg *Package) computeTestProfile(mod *Module) {
+	pkg.Tests = newTestProfile()
+
+	// Declared names in code files: functions, types, Type.Methods
+	declared := ds.NewSet[string]()
+	methods := make(map[string][]string) // method name => list of Type.Method
+	for _, fn := range pkg.Functions {
+		declared.Add(fn.Name)
+		if typeName, methodName, ok := strings.Cut(fn.Name, "."); ok {
+			methods[methodName] = append(methods[methodName], typeName+"."+methodName)
+		}
+	}
+	for _, f := range pkg.Files {
+		if f.Type == FILE_CODE && f.syntax != nil {
+			declared.AddItems(declaredTypes(f.syntax))
+		}
+	}
+
+	importPath := mod.ImportPath(pkg)
+	targeted := ds.NewSet[string]()
+	for _, f := range pkg.Files {
+		if f.Type != FILE_TEST || f.syntax == nil {
+			continue
+		}
+		isExternal := strings.HasSuffix(f.syntax.Name.Name, "_test")
+		if isExternal {
+			pkg.Tests.ExternalFiles += 1
+		} else {
+			pkg.Tests.InternalFiles += 1
+		}
+		pkgAlias := importAlias(f.syntax, importPath)
+
+		for _, decl := range f.syntax.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil {
+				continue
+			}
+			kind, suffix, ok := classifyTestFunc(fn.Name.Name)
+			if !ok {
+				continue
+			}
+			targets := ds.NewSet[string]()
+			targets.AddItems(conventionTargets(suffix, declared))
+			targets.AddItems(callTargets(fn.Body, pkgAlias, isExternal, declared, methods))
+			targetList := targets.Items()
+			slices.Sort(targetList)
+			test := &TestFunc{
+				Name:     fn.Name.Name,
+				Kind:     kind,
+				Package:  pkg.Name,
+				File:     f.Name,
+				Line:     mod.fset.Position(fn.Pos()).Line,
+				External: isExternal,
+				Targets:  targetList,
+			}
+			pkg.Tests.Tests = append(pkg.Tests.Tests, test)
+			pkg.Tests.Kinds[kind] += 1
+			targeted.AddItems(test.Targets)
+		}
+	}
+
+	// Exported functions and methods without tests
+	for _, fn := range pkg.Functions {
+		if fn.Type != PUB_FUNCTION && fn.Type != PUB_METHOD {
+			continue
+		}
+		typeName, _, isMethod := strings.Cut(fn.Name, ".")
+		if isMethod && !ast.IsExported(typeName) {
+			continue // method of private type, not part of API
+		}
+		pkg.Tests.Exported += 1
+		if targeted.Has(fn.Name) {
+			pkg.Tests.Tested += 1
+			continue
+		}
+		pkg.Tests.Untested = append(pkg.Tests.Untested, &Symbol{
+			Name:    fn.Name,
+			Type:    fn.Type,
+			Package: fn.Package,
+			File:    fn.File,
+			Line:    fn.Line,
+		})
+	}
+	slices.SortFunc(pkg.Tests.Tests, compareTestFuncs)
+}
+
+// Classify test function by prefix, return kind and name suffix:
+// TestFoo, BenchmarkFoo, FuzzFoo, ExampleFoo, Example
+func classifyTestFunc(name string) (TestKind, string, bool) {
+	for _, kind := range testKinds {
+		suffix, ok := strings.CutPrefix(name, string(kind))
+		if !ok {
+			continue
+		}
+		// TestMain is setup, not a test
+		if kind == TEST_FUNC && suffix == "Main" {
+			return "", "", false
+		}
+		// Suffix must not start with lowercase: Testify is not a test
+		if suffix == "" || suffix[0] == '_' || !isLower(suffix[0]) {
+			return kind, strings.TrimPrefix(suffix, "_"), true
+		}
+	}
+	return "", "", false
+}
+
+// Get targets from test name convention: Foo, Type_Method, Foo_case, FooBar
+func conventionTargets(suffix string, declared *ds.Set[string]) []string {
+	if suffix == "" {
+		return nil
+	}
+	// Match first letter case-insensitively: TestNewFoo can test newFoo
+	has := func(name string) (string, bool) {
+		for _, candidate := range []string{name, lowerFirst(name), upperFirst(name)} {
+			if declared.Has(candidate) {
+				return candidate, true
+			}
+		}
+		return "", false
+	}
+	parts := strings.Split(suffix, "_")
+	if len(parts) >= 2 {
+		if name, ok := has(parts[0] + "." + parts[1]); ok {
+			return []string{name}
+		}
+	}
+	if name, ok := has(parts[0]); ok {
+		return []string{name}
+	}
+	// Longest declared name that prefixes the test name: TestParseFile => Parse;
+	// on equal length, exact case first (Parse before parse), then sorted order
+	best, isBestExact := "", false
+	names := declared.Items()
+	slices.Sort(names)
+	for _, name := range names {
+		if strings.Contains(name, ".") {
+			continue
+		}
+		isExact := startsWith(parts[0], name)
+		if !isExact && !startsWith(parts[0], upperFirst(name)) {
+			continue
+		}
+		if len(name) > len(best) || (len(name) == len(best) && isExact && !isBestExact) {
+			best, isBestExact = name, isExact
+		}
+	}
+	if best == "" {
+		return nil
+	}
+	return []string{best}
+}
+
+// Get targets from calls and composite literals in test body:
+// Foo(), pkg.Foo() for external tests, x.Method(), Type{}
+func callTargets(body *ast.BlockStmt, pkgAlias string, isExternal bool, declared *ds.Set[string], methods map[string][]string) []string {
+	targets := make([]string, 0)
+	if body == nil {
+		return targets
+	}
+	// Get target name from identifier or package selector
+	nameOf := func(expr ast.Expr) (string, bool) {
+		switch e := expr.(type) {
+		case *ast.Ident:
+			return e.Name, !isExternal && declared.Has(e.Name)
+		case *ast.SelectorExpr:
+			x, ok := e.X.(*ast.Ident)
+			return e.Sel.Name, ok && isExternal && x.Name == pkgAlias && declared.Has(e.Sel.Name)
+		}
+		return "", false
+	}
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.CallExpr:
+			if name, ok := nameOf(n.Fun); ok {
+				targets = append(targets, name)
+			} else if sel, ok := n.Fun.(*ast.SelectorExpr); ok {
+				targets = append(targets, methods[sel.Sel.Name]...)
+			}
+		case *ast.CompositeLit:
+			if name, ok := nameOf(n.Type); ok {
+				targets = append(targets, name)
+			}
+		}
+		return true
+	})
+	return targets
+}
+
+// Get declared type names of file
+func declaredTypes(syntax *ast.File) []string {
+	names := make([]string, 0)
+	for _, decl := range syntax.Decls {
+		d, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range d.Specs {
+			if s, ok := spec.(*ast.TypeSpec); ok {
+				names = append(names, s.Name.Name)
+			}
+		}
+	}
+	return names
+}
+
+// Get name used to refer to the imported package path in file, blank if not imported
+func importAlias(syntax *ast.File, importPath string) string {
+	for _, spec := range syntax.Imports {
+		if strings.Trim(spec.Path.Value, "\"") != importPath {
+			continue
+		}
+		if spec.Name != nil {
+			return spec.Name.Name
+		}
+		return importPath[strings.LastIndex(importPath, "/")+1:]
+	}
+	return ""
+}
+
+// Packages without test functions, sorted by name
+func (mod Module) UntestedPackages() []string {
+	names := make(map[string]bool)
+	for _, pkg := range mod.Packages {
+		if pkg.Tests.TestCount() == 0 {
+			names[pkg.Name] = true
+		}
+	}
+	return slices.Sorted(maps.Keys(names))
+}
+
+// Sort test functions by package, file, line
+func compareTestFuncs(a, b *TestFunc) int {
+	return cmp.Or(
+		cmp.Compare(a.Package, b.Package),
+		cmp.Compare(a.File, b.File),
+		cmp.Compare(a.Line, b.Line),
+	)
+}
+
+// Check if byte is lowercase letter
+func isLower(b byte) bool {
+	return 'a' <= b && b <= 'z'
+}
+
+// Lowercase first letter
+func lowerFirst(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+// Uppercase first letter
+func upperFirst(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
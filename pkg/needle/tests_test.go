@@ -0,0 +1,35 @@
+package needle
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/roidaradal/fn/ds"
+)
+
+func TestConventionTargets(t *testing.T) {
+	tests := []struct {
+		suffix   string
+		declared []string
+		want     []string
+	}{
+		{"", []string{"Foo"}, nil},
+		{"Foo", []string{"Foo", "Bar"}, []string{"Foo"}},
+		{"NewFoo", []string{"newFoo"}, []string{"newFoo"}},
+		{"parse", []string{"Parse"}, []string{"Parse"}},
+		{"Store_Save", []string{"Store", "Store.Save"}, []string{"Store.Save"}},
+		{"Store_Load", []string{"Store", "Store.Save"}, []string{"Store"}},
+		{"Foo_empty", []string{"Foo"}, []string{"Foo"}},
+		{"ParseFile", []string{"Pars", "Parse", "File"}, []string{"Parse"}},
+		{"ParseFile", []string{"parse", "Parse"}, []string{"Parse"}}, // tie: exact case first
+		{"ParseFile", []string{"parse"}, []string{"parse"}},
+		{"StoreSave", []string{"Store.Save"}, nil}, // methods only match Type_Method
+		{"Missing", []string{"Foo"}, nil},
+	}
+	for _, tt := range tests {
+		got := conventionTargets(tt.suffix, ds.SetFrom(tt.declared))
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("conventionTargets(%q, %v) = %v, want %v", tt.suffix, tt.declared, got, tt.want)
+		}
+	}
+}
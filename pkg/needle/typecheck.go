@@ -0,0 +1,321 @@
+package needle
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"github.com/roidaradal/fn/dict"
+)
+
+// Type-check module packages: code files of each package, then its test files.
+// Imports outside the module are not resolved, so their uses have no type
+func typeCheckModule(ctx context.Context, mod *Module) error {
+	mod.checker = newTypeChecker(ctx, mod)
+	for _, pkg := range mod.Packages {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		mod.checker.check(pkg)
+		mod.checker.checkTests(pkg)
+	}
+	return ctx.Err()
+}
+
+// Type-checks module packages from their parsed files, on demand, keyed by import path;
+// imports outside the module fail and are reported as type errors (ignored).
+// Packages are not checked once ctx is done
+type typeChecker struct {
+	ctx         context.Context
+	mod         *Module
+	packages    map[string]*Package       // import path => Package
+	checked     map[string]*types.Package // import path => checked package (nil while checking)
+	infos       map[string]*types.Info    // import path => type info of code files
+	fileInfos   map[*ast.File]*types.Info // parsed file => type info (code and test files)
+	pkgNames    map[string]string         // import path => package name
+	importPaths map[string]string         // package name => import path
+}
+
+// Create new type checker for module
+func newTypeChecker(ctx context.Context, mod *Module) *typeChecker {
+	c := &typeChecker{
+		ctx:         ctx,
+		mod:         mod,
+		packages:    make(map[string]*Package),
+		checked:     make(map[string]*types.Package),
+		infos:       make(map[string]*types.Info),
+		fileInfos:   make(map[*ast.File]*types.Info),
+		pkgNames:    make(dict.StringMap),
+		importPaths: make(dict.StringMap),
+	}
+	for _, pkg := range mod.Packages {
+		importPath := mod.ImportPath(pkg)
+		c.packages[importPath] = pkg
+		c.pkgNames[importPath] = pkg.Name
+		c.importPaths[pkg.Name] = importPath
+	}
+	return c
+}
+
+// Import module package (types.Importer)
+func (c *typeChecker) Import(path string) (*types.Package, error) {
+	pkg, ok := c.packages[path]
+	if !ok {
+		return nil, fmt.Errorf("package %q is outside the module", path)
+	}
+	tpkg, _ := c.check(pkg)
+	if err := c.ctx.Err(); err != nil {
+		return nil, err
+	}
+	if tpkg == nil {
+		return nil, fmt.Errorf("import cycle through %q", path)
+	}
+	return tpkg, nil
+}
+
+// Type-check package code files, return checked package and type info
+func (c *typeChecker) check(pkg *Package) (*types.Package, *types.Info) {
+	importPath := c.mod.ImportPath(pkg)
+	if tpkg, ok := c.checked[importPath]; ok {
+		return tpkg, c.infos[importPath]
+	}
+	if c.ctx.Err() != nil {
+		return nil, nil
+	}
+	c.checked[importPath] = nil // mark as checking: import cycle guard
+	files := codeSyntaxTrees(pkg)
+	tpkg, info := c.checkFiles(importPath, files, c)
+	c.checked[importPath] = tpkg
+	c.infos[importPath] = info
+	for _, syntax := range files {
+		c.fileInfos[syntax] = info
+	}
+	return tpkg, info
+}
+
+// Type-check package test files: in-package test files together with the code files,
+// then the external test package (foo_test), which imports the package with its test files
+func (c *typeChecker) checkTests(pkg *Package) {
+	tpkg, _ := c.check(pkg)
+	if tpkg == nil {
+		return
+	}
+	tests, externalTests := make([]*ast.File, 0), make([]*ast.File, 0)
+	for _, f := range pkg.Files {
+		if f.Type != FILE_TEST || f.syntax == nil {
+			continue
+		}
+		if endsWith(f.syntax.Name.Name, "_test") {
+			externalTests = append(externalTests, f.syntax)
+		} else {
+			tests = append(tests, f.syntax)
+		}
+	}
+	importPath := c.mod.ImportPath(pkg)
+	if len(tests) > 0 {
+		var info *types.Info
+		tpkg, info = c.checkFiles(importPath, append(codeSyntaxTrees(pkg), tests...), c)
+		for _, syntax := range tests {
+			c.fileInfos[syntax] = info
+		}
+	}
+	if len(externalTests) > 0 {
+		_, info := c.checkFiles(importPath+"_test", externalTests, testImporter{c, importPath, tpkg})
+		for _, syntax := range externalTests {
+			c.fileInfos[syntax] = info
+		}
+	}
+}
+
+// Type-check files as package with given import path
+func (c *typeChecker) checkFiles(importPath string, files []*ast.File, importer types.Importer) (*types.Package, *types.Info) {
+	info := &types.Info{
+		Types:      make(map[ast.Expr]types.TypeAndValue),
+		Defs:       make(map[*ast.Ident]types.Object),
+		Uses:       make(map[*ast.Ident]types.Object),
+		Selections: make(map[*ast.SelectorExpr]*types.Selection),
+	}
+	config := &types.Config{
+		Importer:    importer,
+		FakeImportC: true,
+		Error:       func(error) {}, // keep checking: external imports are not resolved
+	}
+	tpkg, _ := config.Check(importPath, c.mod.fset, files, info)
+	return tpkg, info
+}
+
+// Get type info of parsed file, nil if not type-checked
+func (c *typeChecker) fileInfo(f *File) *types.Info {
+	if f.syntax == nil {
+		return nil
+	}
+	return c.fileInfos[f.syntax]
+}
+
+// Importer of external test package: the package under test includes its in-package test files
+type testImporter struct {
+	checker *typeChecker
+	path    string         // import path of package under test
+	testPkg *types.Package // package under test, checked with its test files
+}
+
+// Import module package, or the package under test (types.Importer)
+func (i testImporter) Import(path string) (*types.Package, error) {
+	if path == i.path {
+		return i.testPkg, nil
+	}
+	return i.checker.Import(path)
+}
+
+// Parsed code files of package
+func codeSyntaxTrees(pkg *Package) []*ast.File {
+	files := make([]*ast.File, 0)
+	for _, f := range pkg.Files {
+		if f.Type == FILE_CODE && f.syntax != nil {
+			files = append(files, f.syntax)
+		}
+	}
+	return files
+}
+
+// Get function ID of module function or method object
+func (c *typeChecker) funcID(fn *types.Func) string {
+	if fn.Pkg() == nil {
+		return ""
+	}
+	pkgName, ok := c.pkgNames[fn.Pkg().Path()]
+	if !ok {
+		return ""
+	}
+	name := fn.Name()
+	if recv := fn.Type().(*types.Signature).Recv(); recv != nil {
+		name = c.typeName(recv.Type()) + "." + name
+	}
+	return symbolID(pkgName, name)
+}
+
+// Get module-wide key of package-level object: importPath.Name, or importPath.Type.Method for methods;
+// blank for local objects, struct fields and predeclared objects
+func (c *typeChecker) objectKey(obj types.Object) string {
+	if obj.Pkg() == nil {
+		return ""
+	}
+	if fn, ok := obj.(*types.Func); ok {
+		if recv := fn.Type().(*types.Signature).Recv(); recv != nil {
+			return obj.Pkg().Path() + "." + c.typeName(recv.Type()) + "." + obj.Name()
+		}
+	}
+	if obj.Parent() != obj.Pkg().Scope() {
+		return ""
+	}
+	return obj.Pkg().Path() + "." + obj.Name()
+}
+
+// Get type ID of named type (pointer and type arguments removed)
+func (c *typeChecker) typeID(t types.Type) string {
+	named, ok := types.Unalias(derefType(t)).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return ""
+	}
+	pkgName, ok := c.pkgNames[named.Obj().Pkg().Path()]
+	if !ok {
+		return ""
+	}
+	return symbolID(pkgName, named.Obj().Name())
+}
+
+// Get embedded type IDs of struct or interface type spec (*ID if embedded as pointer);
+// type unions and approximations of constraints are skipped
+func (c *typeChecker) embeddedTypes(spec *ast.TypeSpec, info *types.Info) []string {
+	fields := make([]*ast.Field, 0)
+	switch t := spec.Type.(type) {
+	case *ast.StructType:
+		fields = t.Fields.List
+	case *ast.InterfaceType:
+		fields = t.Methods.List
+	}
+	embeds := make([]string, 0)
+	for _, field := range fields {
+		if len(field.Names) > 0 {
+			continue
+		}
+		expr, prefix := field.Type, ""
+		if star, ok := expr.(*ast.StarExpr); ok {
+			expr, prefix = star.X, "*"
+		}
+		switch expr.(type) {
+		case *ast.Ident, *ast.SelectorExpr, *ast.IndexExpr, *ast.IndexListExpr:
+			embeds = append(embeds, prefix+c.exprTypeID(info, expr))
+		}
+	}
+	return embeds
+}
+
+// Get type ID of named type expression (type arguments removed): package.Name for module types,
+// importPath.Name for external types, Name for predeclared types and type parameters
+func (c *typeChecker) exprTypeID(info *types.Info, expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		obj, ok := info.Uses[e].(*types.TypeName)
+		if !ok || obj.Pkg() == nil {
+			return e.Name
+		}
+		if _, isTypeParam := obj.Type().(*types.TypeParam); isTypeParam {
+			return e.Name
+		}
+		return c.qualifiedID(obj.Pkg().Path(), e.Name)
+	case *ast.SelectorExpr:
+		if x, ok := e.X.(*ast.Ident); ok {
+			if pkgName, ok := info.Uses[x].(*types.PkgName); ok {
+				return c.qualifiedID(pkgName.Imported().Path(), e.Sel.Name)
+			}
+		}
+	case *ast.IndexExpr:
+		return c.exprTypeID(info, e.X)
+	case *ast.IndexListExpr:
+		return c.exprTypeID(info, e.X)
+	}
+	return types.ExprString(expr)
+}
+
+// Get ID of symbol of package: package.Name for module packages, importPath.Name otherwise
+func (c *typeChecker) qualifiedID(importPath, name string) string {
+	if pkgName, ok := c.pkgNames[importPath]; ok {
+		return symbolID(pkgName, name)
+	}
+	return importPath + "." + name
+}
+
+// Get type name of receiver type (pointer and type arguments removed)
+func (c *typeChecker) typeName(t types.Type) string {
+	if named, ok := types.Unalias(derefType(t)).(*types.Named); ok {
+		return named.Obj().Name()
+	}
+	return types.TypeString(t, nil)
+}
+
+// Get checked named type of module type
+func (c *typeChecker) named(t *NamedType) *types.Named {
+	if t == nil {
+		return nil
+	}
+	tpkg, err := c.Import(c.importPaths[t.Package])
+	if err != nil {
+		return nil
+	}
+	obj, ok := tpkg.Scope().Lookup(t.Name).(*types.TypeName)
+	if !ok {
+		return nil
+	}
+	named, _ := types.Unalias(obj.Type()).(*types.Named)
+	return named
+}
+
+// Remove pointer from type
+func derefType(t types.Type) types.Type {
+	if ptr, ok := types.Unalias(t).(*types.Pointer); ok {
+		return ptr.Elem()
+	}
+	return t
+}
@@ -0,0 +1,207 @@
+package needle
+
+import (
+	"cmp"
+	"context"
+	"go/ast"
+	"go/types"
+	"slices"
+
+	"github.com/roidaradal/fn/ds"
+)
+
+// Named type of the module, with its method set and relationships.
+// Types are identified by package.Name (Name only for the root package)
+type NamedType struct {
+	ID              string   `json:"id"`              // package.Name
+	Name            string   `json:"name"`            // type name
+	Type            CodeType `json:"type"`            // (public/private) x (struct/interface/alias)
+	Package         string   `json:"package"`         // package name
+	File            string   `json:"file"`            // file name
+	Line            int      `json:"line"`            // line number
+	Methods         []string `json:"methods"`         // method set of *T (own and promoted), interface methods for interfaces
+	PointerMethods  []string `json:"pointerMethods"`  // methods only in method set of *T, not T
+	Embeds          []string `json:"embeds"`          // embedded type IDs, external types are qualified by import path
+	Implements      []string `json:"implements"`      // interface IDs implemented; *ID if only *T implements it
+	Implementations []string `json:"implementations"` // for interfaces: implementing type IDs; *ID if only *T implements it
+	Partial         bool     `json:"partial"`         // interface embeds external interfaces: method set unknown, not matched
+	Constraint      bool     `json:"constraint"`      // interface with type union: type constraint, not matched
+	Generic         bool     `json:"generic"`         // generic interface: implementations need type arguments, not matched
+}
+
+// Type relationships: method sets, interface implementations, embedded types
+type TypeGraph struct {
+	Types []*NamedType `json:"types"` // module named types, sorted by package, file, line
+}
+
+// Interfaces with zero or exactly one implementation, sorted by ID
+func (g TypeGraph) LonelyInterfaces() []*NamedType {
+	interfaces := make([]*NamedType, 0)
+	for _, t := range g.Types {
+		if t.IsInterface() && !t.Partial && !t.Constraint && !t.Generic && len(t.Implementations) <= 1 {
+			interfaces = append(interfaces, t)
+		}
+	}
+	slices.SortFunc(interfaces, func(a, b *NamedType) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return interfaces
+}
+
+// Check if named type is an interface
+func (t NamedType) IsInterface() bool {
+	return t.Type == PUB_INTERFACE || t.Type == PRIV_INTERFACE
+}
+
+// Compute module type graph from the type-checked code files: named types of each package,
+// method sets (own and promoted methods), and interface implementations (types.Implements)
+func computeTypeGraph(ctx context.Context, mod *Module) error {
+	type declaredType struct {
+		node    *NamedType
+		typ     types.Type
+		isAlias bool
+	}
+	declared := make([]declaredType, 0)
+	seen := ds.NewSet[string]() // type IDs, first declaration kept (e.g. files for other platforms)
+	for _, pkg := range mod.Packages {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		tpkg, info := mod.checker.check(pkg)
+		if tpkg == nil {
+			continue
+		}
+		for _, f := range pkg.Files {
+			if f.Type != FILE_CODE || f.syntax == nil {
+				continue
+			}
+			for _, decl := range f.syntax.Decls {
+				d, ok := decl.(*ast.GenDecl)
+				if !ok {
+					continue
+				}
+				for _, spec := range d.Specs {
+					s, ok := spec.(*ast.TypeSpec)
+					if !ok {
+						continue
+					}
+					obj, ok := info.Defs[s.Name].(*types.TypeName)
+					id := symbolID(pkg.Name, s.Name.Name)
+					if !ok || seen.Has(id) {
+						continue
+					}
+					seen.Add(id)
+					node := newNamedType(mod, pkg, f, s)
+					node.Embeds = mod.checker.embeddedTypes(s, info)
+					collectMethods(node, obj.Type())
+					declared = append(declared, declaredType{node, obj.Type(), obj.IsAlias()})
+				}
+			}
+		}
+	}
+
+	// Interface implementations: T or only *T implements the interface
+	for _, iface := range declared {
+		if !iface.node.IsInterface() || iface.node.Partial || iface.node.Constraint || iface.node.Generic {
+			continue
+		}
+		it, ok := iface.typ.Underlying().(*types.Interface)
+		if !ok || it.NumMethods() == 0 {
+			continue
+		}
+		for _, t := range declared {
+			if t.node.IsInterface() || t.isAlias || types.IsInterface(t.typ) {
+				continue
+			}
+			prefix := ""
+			if !types.Implements(t.typ, it) {
+				if !types.Implements(types.NewPointer(t.typ), it) {
+					continue
+				}
+				prefix = "*"
+			}
+			iface.node.Implementations = append(iface.node.Implementations, prefix+t.node.ID)
+			t.node.Implements = append(t.node.Implements, prefix+iface.node.ID)
+		}
+	}
+
+	for _, t := range declared {
+		slices.Sort(t.node.Implements)
+		slices.Sort(t.node.Implementations)
+		mod.TypeGraph.Types = append(mod.TypeGraph.Types, t.node)
+	}
+	slices.SortFunc(mod.TypeGraph.Types, compareNamedTypes)
+	return nil
+}
+
+// Create named type node of type spec, without methods and relationships
+func newNamedType(mod *Module, pkg *Package, file *File, spec *ast.TypeSpec) *NamedType {
+	return &NamedType{
+		ID:              symbolID(pkg.Name, spec.Name.Name),
+		Name:            spec.Name.Name,
+		Type:            typeSpecCodeType(spec),
+		Package:         pkg.Name,
+		File:            file.Name,
+		Line:            mod.fset.Position(spec.Pos()).Line,
+		Methods:         make([]string, 0),
+		PointerMethods:  make([]string, 0),
+		Embeds:          make([]string, 0),
+		Implements:      make([]string, 0),
+		Implementations: make([]string, 0),
+	}
+}
+
+// Collect method set of named type: interface methods for interfaces, *T method set for other types,
+// and flag interfaces that can't be matched (partial, type constraint, generic)
+func collectMethods(node *NamedType, typ types.Type) {
+	if it, ok := typ.Underlying().(*types.Interface); ok {
+		for method := range it.Methods() {
+			node.Methods = append(node.Methods, method.Name())
+		}
+		if node.IsInterface() {
+			// Embedded interface outside the module: not resolved, method set unknown
+			for embedded := range it.EmbeddedTypes() {
+				node.Partial = node.Partial || embedded.Underlying() == types.Typ[types.Invalid]
+			}
+			node.Constraint = !it.IsMethodSet()
+			named, ok := typ.(*types.Named)
+			node.Generic = ok && named.TypeParams().Len() > 0
+		}
+	} else {
+		valueMethods := types.NewMethodSet(typ)
+		for method := range types.NewMethodSet(types.NewPointer(typ)).Methods() {
+			obj := method.Obj()
+			node.Methods = append(node.Methods, obj.Name())
+			if valueMethods.Lookup(obj.Pkg(), obj.Name()) == nil {
+				node.PointerMethods = append(node.PointerMethods, obj.Name())
+			}
+		}
+	}
+	slices.Sort(node.Methods)
+	slices.Sort(node.PointerMethods)
+}
+
+// Classify type spec as (public/private) x (struct/interface/alias)
+func typeSpecCodeType(spec *ast.TypeSpec) CodeType {
+	codeType := classifyTypeSpec(spec)
+	if ast.IsExported(spec.Name.Name) {
+		return codeType
+	}
+	switch codeType {
+	case PUB_STRUCT:
+		return PRIV_STRUCT
+	case PUB_INTERFACE:
+		return PRIV_INTERFACE
+	}
+	return PRIV_ALIAS
+}
+
+// Sort named types by package, file, line
+func compareNamedTypes(a, b *NamedType) int {
+	return cmp.Or(
+		cmp.Compare(a.Package, b.Package),
+		cmp.Compare(a.File, b.File),
+		cmp.Compare(a.Line, b.Line),
+		cmp.Compare(a.ID, b.ID),
+	)
+}
@@ -0,0 +1,82 @@
+package needle
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestTypeGraph(t *testing.T) {
+	mod, err := Analyze(context.Background(), "testdata/modules/types", WithoutModuleGraph())
+	if err != nil {
+		t.Fatal(err)
+	}
+	types := make(map[string]*NamedType)
+	for _, namedType := range mod.TypeGraph.Types {
+		types[namedType.ID] = namedType
+	}
+
+	tests := []struct {
+		id              string
+		methods         []string
+		pointerMethods  []string
+		embeds          []string
+		implements      []string
+		implementations []string
+	}{
+		{"shapes.Shape", []string{"Area"}, nil, nil, nil, []string{"impl.Box", "impl.Framed", "impl.Square"}},
+		{"shapes.Named", []string{"Area", "Name"}, nil, []string{"shapes.Shape"}, nil, []string{"*impl.Square", "impl.Framed"}},
+		{"impl.Square", []string{"Area", "Name"}, []string{"Name"}, nil, []string{"*shapes.Named", "shapes.Shape"}, nil},
+		{"impl.Box", []string{"Area", "Get"}, nil, nil, []string{"shapes.Shape"}, nil},
+		{"impl.Circle", []string{"Area"}, nil, nil, nil, nil},
+		{"impl.Framed", []string{"Area", "Name"}, nil, []string{"*impl.Square", "io.Writer"}, []string{"shapes.Named", "shapes.Shape"}, nil},
+		{"shapes.Float", nil, nil, nil, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			namedType, ok := types[tt.id]
+			if !ok {
+				t.Fatalf("type %s not found", tt.id)
+			}
+			check := func(field string, got, want []string) {
+				if !slices.Equal(got, want) && (len(got) > 0 || len(want) > 0) {
+					t.Errorf("%s %s = %v, want %v", tt.id, field, got, want)
+				}
+			}
+			check("methods", namedType.Methods, tt.methods)
+			check("pointerMethods", namedType.PointerMethods, tt.pointerMethods)
+			check("embeds", namedType.Embeds, tt.embeds)
+			check("implements", namedType.Implements, tt.implements)
+			check("implementations", namedType.Implementations, tt.implementations)
+		})
+	}
+
+	flags := []struct {
+		id                           string
+		partial, constraint, generic bool
+	}{
+		{"shapes.Shape", false, false, false},
+		{"shapes.Source", true, false, false},
+		{"shapes.Number", false, true, false},
+		{"shapes.Getter", false, false, true},
+	}
+	for _, tt := range flags {
+		namedType := types[tt.id]
+		if namedType == nil {
+			t.Errorf("type %s not found", tt.id)
+			continue
+		}
+		if namedType.Partial != tt.partial || namedType.Constraint != tt.constraint || namedType.Generic != tt.generic {
+			t.Errorf("%s partial, constraint, generic = %v, %v, %v, want %v, %v, %v", tt.id,
+				namedType.Partial, namedType.Constraint, namedType.Generic, tt.partial, tt.constraint, tt.generic)
+		}
+	}
+
+	lonely := make([]string, 0)
+	for _, iface := range mod.TypeGraph.LonelyInterfaces() {
+		lonely = append(lonely, iface.ID)
+	}
+	if !slices.Equal(lonely, []string{}) {
+		t.Errorf("LonelyInterfaces() = %v, want []", lonely)
+	}
+}
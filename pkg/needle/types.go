@@ -0,0 +1,307 @@
+package needle
+
+import (
+	"go/ast"
+	"go/token"
+
+	"github.com/roidaradal/fn/dict"
+	"github.com/roidaradal/fn/list"
+)
+
+// Go module
+type Module struct {
+	Path        string           `json:"path"`     // Go module filesystem path
+	Name        string           `json:"name"`     // Go module name
+	Nodes       map[string]*Node `json:"nodes"`    // Mapping of subfolders to Node inside Go module
+	Packages    []*Package       `json:"packages"` // list of Package objects
+	Deps        `json:"deps"`
+	Stats       `json:"stats"`
+	Code        `json:"code"`
+	Extra       map[string]Metrics `json:"extra"` // Analyzer name => module metrics
+	TypeGraph   TypeGraph          `json:"typeGraph"`
+	CallGraph   CallGraph          `json:"callGraph"`
+	ModuleGraph ModuleGraph        `json:"moduleGraph"`
+	options     *Options
+	workers     chan struct{} // worker pool semaphore
+	progress    *tracker
+	analyzers   []Analyzer
+	fset        *token.FileSet // positions of parsed files
+	highlight   [][2]string    // dependency graph edges [from, to] to highlight in report
+	goMod       *goModFile     // parsed go.mod: requirements, replacements
+	checker     *typeChecker   // type-checked packages
+}
+
+// Dependencies info
+type Deps struct {
+	Of            dict.StringListMap `json:"of"`            // Internal package => list of subpackges it depends on
+	InternalUsers dict.StringListMap `json:"internalUsers"` // Internal package => list of subpackages that directly use it
+	ExternalUsers dict.StringListMap `json:"externalUsers"` // External dependency => list of subpackages that directly use it
+	External      []string           `json:"external"`      // List of external subpackages
+	Independent   []string           `json:"independent"`   // List of independent subpackages (not in dependency DAG)
+	Levels        map[int][]string   `json:"levels"`        // Non-independent subpackage levels (0 = sink)
+	Nodes         dict.StringMap     `json:"nodes"`         // Non-independent subpackage => {x: xPosition, y: yPosition}
+	Edges         []string           `json:"edges"`         // List of node1-node2 edges
+	Coupling      []*Coupling        `json:"coupling"`      // Internal edge weights, sorted by package names
+}
+
+// Internal dependency edge weight: symbols of To referenced from From
+type Coupling struct {
+	From    string   `json:"from"`    // dependent package name
+	To      string   `json:"to"`      // dependency package name
+	Symbols []string `json:"symbols"` // distinct symbols of To referenced from From, sorted
+	Files   int      `json:"files"`   // files of From that import To
+}
+
+// Stats info
+type Stats struct {
+	PackageCount int                       `json:"packageCount"`
+	FileCount    int                       `json:"fileCount"`
+	LineCount    int                       `json:"lineCount"`
+	CharCount    int                       `json:"charCount"`
+	Packages     dict.Counter[PackageType] `json:"packages"`
+	Files        dict.Counter[FileType]    `json:"files"`
+	FileLines    dict.Counter[FileType]    `json:"fileLines"`
+	FileChars    dict.Counter[FileType]    `json:"fileChars"`
+}
+
+// Code info
+type Code struct {
+	Blocks   dict.Counter[BlockType] `json:"blocks"`
+	Types    dict.Counter[CodeType]  `json:"types"`
+	Lines    dict.Counter[LineType]  `json:"lines"`
+	Chars    dict.Counter[LineType]  `json:"chars"`
+	Errors   ErrorProfile            `json:"errors"`
+	Docs     DocProfile              `json:"docs"`
+	Tests    TestProfile             `json:"tests"`
+	Coverage Coverage                `json:"coverage"`
+	DeadCode DeadCode                `json:"deadCode"`
+}
+
+// Create new Module
+func newModule() *Module {
+	return &Module{
+		Nodes:    make(map[string]*Node),
+		Packages: make([]*Package, 0),
+		Extra:    make(map[string]Metrics),
+		TypeGraph: TypeGraph{
+			Types: make([]*NamedType, 0),
+		},
+		CallGraph: CallGraph{
+			Calls: make([]*Call, 0),
+		},
+		ModuleGraph: ModuleGraph{
+			Modules: make([]*ExternalModule, 0),
+			Majors:  make(map[string][]string),
+		},
+		fset: token.NewFileSet(),
+		Deps: Deps{
+			Of:            make(dict.StringListMap),
+			InternalUsers: make(dict.StringListMap),
+			ExternalUsers: make(dict.StringListMap),
+			External:      make([]string, 0),
+			Independent:   make([]string, 0),
+			Levels:        make(map[int][]string),
+			Coupling:      make([]*Coupling, 0),
+		},
+		Code: Code{
+			Blocks:   make(dict.Counter[BlockType]),
+			Types:    make(dict.Counter[CodeType]),
+			Lines:    make(dict.Counter[LineType]),
+			Chars:    make(dict.Counter[LineType]),
+			Docs:     newDocProfile(),
+			Tests:    newTestProfile(),
+			DeadCode: newDeadCode(),
+		},
+		Stats: Stats{
+			Packages:  make(dict.Counter[PackageType]),
+			Files:     make(dict.Counter[FileType]),
+			FileLines: make(dict.Counter[FileType]),
+			FileChars: make(dict.Counter[FileType]),
+		},
+	}
+}
+
+type (
+	NodeEntry  = dict.Entry[string, *Node]
+	CountEntry = dict.Entry[string, int]
+)
+
+type (
+	PackageType string
+	FileType    string
+	LineType    string
+	BlockType   string
+	CodeType    string
+)
+
+const (
+	PKG_LIB  PackageType = "Lib"
+	PKG_MAIN PackageType = "Main"
+)
+const (
+	FILE_CODE FileType = "Code"
+	FILE_TEST FileType = "Test"
+)
+const (
+	LINE_CODE    LineType = "Codes"
+	LINE_ERROR   LineType = "Error"
+	LINE_HEAD    LineType = "Head"
+	LINE_COMMENT LineType = "Comment"
+	LINE_SPACE   LineType = "Space"
+)
+
+const (
+	CODE_FUNCTION BlockType = "Function"
+	CODE_TYPE     BlockType = "Type"
+	CODE_GLOBAL   BlockType = "Global"
+)
+
+const (
+	NOT_CODE       CodeType = "not_code"
+	CODE_GROUP     CodeType = "group"
+	PUB_FUNCTION   CodeType = "PubFunction"
+	PRIV_FUNCTION  CodeType = "PrivFunction"
+	PUB_METHOD     CodeType = "PubMethod"
+	PRIV_METHOD    CodeType = "PrivMethod"
+	PUB_STRUCT     CodeType = "PubStruct"
+	PRIV_STRUCT    CodeType = "PrivStruct"
+	PUB_INTERFACE  CodeType = "PubInterface"
+	PRIV_INTERFACE CodeType = "PrivInterface"
+	PUB_ALIAS      CodeType = "PubAlias"
+	PRIV_ALIAS     CodeType = "PrivAlias"
+	PUB_CONST      CodeType = "PubConst"
+	PRIV_CONST     CodeType = "PrivConst"
+	PUB_VAR        CodeType = "PubVar"
+	PRIV_VAR       CodeType = "PrivVar"
+)
+
+// File system folder
+type Node struct {
+	Folders []string `json:"folders"`
+	Files   []string `json:"files"`
+}
+
+// Create new Node
+func newNode() *Node {
+	return &Node{
+		Folders: make([]string, 0),
+		Files:   make([]string, 0),
+	}
+}
+
+// Get number of files for Node
+func (n Node) FileCount() int {
+	return len(n.Files)
+}
+
+// Go Package object
+type Package struct {
+	Name      string                  `json:"name"`
+	Type      PackageType             `json:"type"`
+	Files     []*File                 `json:"files"`
+	Deps      map[string]bool         `json:"deps"` // dependency => isInternal
+	Blocks    dict.Counter[BlockType] `json:"blocks"`
+	Codes     dict.Counter[CodeType]  `json:"codes"`
+	FileTypes dict.Counter[FileType]  `json:"fileTypes"`
+	FileLines dict.Counter[FileType]  `json:"fileLines"`
+	FileChars dict.Counter[FileType]  `json:"fileChars"`
+	LineTypes dict.Counter[LineType]  `json:"lineTypes"`
+	CharTypes dict.Counter[LineType]  `json:"charTypes"`
+	LineCount int                     `json:"lineCount"`
+	CharCount int                     `json:"charCount"`
+	Extra     map[string]Metrics      `json:"extra"` // Analyzer name => package metrics
+	Errors    ErrorProfile            `json:"errors"`
+	Docs      DocProfile              `json:"docs"`
+	Functions []*Function             `json:"functions"` // functions of code files
+	Tests     TestProfile             `json:"tests"`
+	Coverage  Coverage                `json:"coverage"`
+	DeadCode  DeadCode                `json:"deadCode"`
+}
+
+// Go File object
+type File struct {
+	Name      string                  `json:"name"`
+	Type      FileType                `json:"type"`
+	Lines     []*Line                 `json:"lines"`
+	Deps      map[string]bool         `json:"deps"` // dependency => isInternal
+	Blocks    dict.Counter[BlockType] `json:"blocks"`
+	Codes     dict.Counter[CodeType]  `json:"codes"`
+	LineTypes dict.Counter[LineType]  `json:"lineTypes"`
+	CharTypes dict.Counter[LineType]  `json:"charTypes"`
+	CharCount int                     `json:"charCount"`
+	Extra     map[string]Metrics      `json:"extra"` // Analyzer name => file metrics
+	Errors    ErrorProfile            `json:"errors"`
+	Coverage  Coverage                `json:"coverage"`
+	pkgType   PackageType             // from package header
+	syntax    *ast.File               // parsed syntax tree, nil if file has syntax errors
+}
+
+// Go Line object
+type Line struct {
+	Type     LineType `json:"type"`
+	Length   int      `json:"length"` // character count
+	CodeType `json:"codeType"`
+}
+
+// Package code for lookup
+func (pkg Package) GetCode() string {
+	return pkg.Name
+}
+
+// File code for lookup
+func (f File) GetCode() string {
+	return f.Name
+}
+
+// Create new Line with type: LINE_SPACE
+func newSpaceLine() *Line {
+	return &Line{Type: LINE_SPACE, Length: 1, CodeType: NOT_CODE}
+}
+
+// Create new Line with type: LINE_COMMENT
+func newCommentLine(length int) *Line {
+	return &Line{Type: LINE_COMMENT, Length: length, CodeType: NOT_CODE}
+}
+
+// Create new Line with type: LINE_HEAD
+func newHeadLine(length int) *Line {
+	return &Line{Type: LINE_HEAD, Length: length, CodeType: NOT_CODE}
+}
+
+// Create new Line with type: LINE_ERROR
+func newErrorLine(length int) *Line {
+	return &Line{Type: LINE_ERROR, Length: length, CodeType: NOT_CODE}
+}
+
+// Create new Line with type: LINE_CODE
+func newCodeLine(length int) *Line {
+	return &Line{Type: LINE_CODE, Length: length}
+}
+
+// Return module package names
+func (mod Module) PackageNames() []string {
+	return list.Map(mod.Packages, (*Package).GetCode)
+}
+
+// Return import path of module package
+func (mod Module) ImportPath(pkg *Package) string {
+	return mod.PackageImportPath(pkg.Name)
+}
+
+// Return import path of package name: module name for the root package
+func (mod Module) PackageImportPath(pkgName string) string {
+	if pkgName == "/" {
+		return mod.Name
+	}
+	return mod.Name + "/" + pkgName
+}
+
+// Return package file names
+func (pkg Package) FileNames() []string {
+	return list.Map(pkg.Files, (*File).GetCode)
+}
+
+// Return package file count
+func (pkg *Package) FileCount() int {
+	return len(pkg.Files)
+}
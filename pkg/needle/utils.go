@@ -3,6 +3,7 @@ package needle
 import (
 	"cmp"
 	"fmt"
+	"path"
 	"strings"
 
 	"github.com/roidaradal/fn/number"
@@ -39,6 +40,14 @@ func percentage(num, denom int) string {
 	return fmt.Sprintf("%.0f%%", ratio)
 }
 
+// Return coverage percentage string, - if no statements
+func coveragePercent(coverage Coverage) string {
+	if coverage.Statements == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f%%", coverage.Percent())
+}
+
 // Return average string
 func average(num, denom int) string {
 	if denom == 0 {
@@ -70,3 +79,19 @@ func packageToNodeName(name string) string {
 func nodeToPackageName(name string) string {
 	return str.GuardWith(strings.TrimPrefix(name, "/"), "/")
 }
+
+// Return file path relative to module root: package/file.go, file.go for the root package
+func packageFilePath(pkgName, fileName string) string {
+	if pkgName == "/" {
+		return fileName
+	}
+	return path.Join(pkgName, fileName)
+}
+
+// Return module-wide ID of package symbol: package.Name, Name only for the root package
+func symbolID(pkgName, name string) string {
+	if pkgName == "/" {
+		return name
+	}
+	return pkgName + "." + name
+}
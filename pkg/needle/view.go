@@ -0,0 +1,190 @@
+package needle
+
+import (
+	"html/template"
+)
+
+const (
+	left         = "left"
+	center       = "center"
+	right        = "right"
+	centerLocal  = "center local"
+	centerGlobal = "center global"
+)
+
+// HTML report template data: the analyzed module, and one view per tab with formatted
+// counts and tables (see README for the data model)
+type ReportData struct {
+	ModuleName string
+	Module     *Module // analyzed module, for custom templates
+	Theme      Theme
+	Mod        modView
+	Code       codeView
+	Deps       depsView
+	Docs       docsView
+	Tests      testsView
+	Types      typesView
+	Dead       deadCodeView
+	Calls      callsView
+	Extra      extraView
+}
+
+// Report table: header rows, body row groups, and footer rows (totals);
+// or a message if there are no body rows. Body row groups are sorted and filtered by the report script
+type tableView struct {
+	ID    string // table element ID
+	Head  []rowView
+	Body  []rowGroup
+	Foot  []rowView
+	Empty string
+}
+
+// Body rows that are sorted and filtered together: e.g. a package row and its file rows
+type rowGroup struct {
+	Package string // package name, for the package prefix filter (blank if not filtered)
+	Rows    []rowView
+}
+
+// Report table row
+type rowView []cellView
+
+// Report table cell (th or td): one text span per line, or a button
+type cellView struct {
+	Header  bool
+	Spans   []textView
+	Link    string // link of cell text
+	Button  *buttonView
+	Class   string
+	Title   string
+	Rowspan int
+	Colspan int
+}
+
+// Text span of a cell line
+type textView struct {
+	Text   string
+	Bold   bool
+	Italic bool
+}
+
+// Button that calls a report script function: arguments are escaped as JS values
+type buttonView struct {
+	ID     string
+	Label  string
+	Action template.JS // report script function name
+	Args   []any
+}
+
+type CellOption func(*cellView)
+
+// Create table with empty message
+func newTable(empty string) tableView {
+	return tableView{
+		Head:  make([]rowView, 0),
+		Body:  make([]rowGroup, 0),
+		Foot:  make([]rowView, 0),
+		Empty: empty,
+	}
+}
+
+// Add body row group, with package name for the package prefix filter (blank if not filtered)
+func (t *tableView) addGroup(pkg string, rows ...rowView) {
+	t.Body = append(t.Body, rowGroup{Package: pkg, Rows: rows})
+}
+
+// Create td cell
+func td(text string, options ...CellOption) cellView {
+	return newCell(false, text, options)
+}
+
+// Create th cell
+func th(text string, options ...CellOption) cellView {
+	return newCell(true, text, options)
+}
+
+// Create cell with text and options
+func newCell(isHeader bool, text string, options []CellOption) cellView {
+	c := cellView{Header: isHeader, Spans: []textView{{Text: text}}}
+	for _, opt := range options {
+		opt(&c)
+	}
+	return c
+}
+
+// Create button that shows or hides the detail rows (tab-subTab-list class) of a table
+func toggleButton(tab, subTab, name string) *buttonView {
+	return &buttonView{
+		ID:     "toggle-" + tab + "-" + subTab,
+		Label:  "Show " + name,
+		Action: "toggleList",
+		Args:   []any{tab, subTab, name},
+	}
+}
+
+// Cell option: class
+func withClass(class string) CellOption {
+	return func(c *cellView) {
+		c.Class = class
+	}
+}
+
+// Cell option: title
+func withTitle(title string) CellOption {
+	return func(c *cellView) {
+		c.Title = title
+	}
+}
+
+// Cell option: rowspan
+func withRowspan(rowspan int) CellOption {
+	return func(c *cellView) {
+		c.Rowspan = rowspan
+	}
+}
+
+// Cell option: colspan
+func withColspan(colspan int) CellOption {
+	return func(c *cellView) {
+		c.Colspan = colspan
+	}
+}
+
+// Cell option: link cell text
+func withLink(href string) CellOption {
+	return func(c *cellView) {
+		c.Link = href
+	}
+}
+
+// Cell option: bold text
+func withBold() CellOption {
+	return func(c *cellView) {
+		for i := range c.Spans {
+			c.Spans[i].Bold = true
+		}
+	}
+}
+
+// Cell option: one line per item, instead of text
+func withLines(lines []string) CellOption {
+	return func(c *cellView) {
+		c.Spans = make([]textView, len(lines))
+		for i, line := range lines {
+			c.Spans[i] = textView{Text: line}
+		}
+	}
+}
+
+// Cell option: one line per span, instead of text
+func withSpans(spans []textView) CellOption {
+	return func(c *cellView) {
+		c.Spans = spans
+	}
+}
+
+// Cell option: button instead of text
+func withButton(button *buttonView) CellOption {
+	return func(c *cellView) {
+		c.Button = button
+	}
+}
@@ -0,0 +1,195 @@
+package needle
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/roidaradal/fn/ds"
+	"github.com/roidaradal/fn/lang"
+)
+
+// Known vulnerability affecting a required module version
+type Vulnerability struct {
+	ID         string   `json:"id"`
+	Aliases    []string `json:"aliases"` // e.g. CVE and GHSA identifiers
+	Summary    string   `json:"summary"`
+	Module     string   `json:"module"`     // affected module path
+	Version    string   `json:"version"`    // selected version of module
+	Fixed      []string `json:"fixed"`      // fixed versions newer than version, sorted
+	Packages   []string `json:"packages"`   // vulnerable packages of module (empty = whole module)
+	ImportedBy []string `json:"importedBy"` // internal packages that import a vulnerable package, sorted
+}
+
+// OSV entry (https://ossf.github.io/osv-schema/): fields used for matching
+type osvEntry struct {
+	ID        string   `json:"id"`
+	Aliases   []string `json:"aliases"`
+	Summary   string   `json:"summary"`
+	Withdrawn string   `json:"withdrawn"`
+	Affected  []struct {
+		Package struct {
+			Name      string `json:"name"`
+			Ecosystem string `json:"ecosystem"`
+		} `json:"package"`
+		Ranges []struct {
+			Type   string     `json:"type"`
+			Events []osvEvent `json:"events"`
+		} `json:"ranges"`
+		EcosystemSpecific struct {
+			Imports []struct {
+				Path string `json:"path"`
+			} `json:"imports"`
+		} `json:"ecosystem_specific"`
+	} `json:"affected"`
+}
+
+// OSV range event: version (without v prefix) where a range starts or ends
+type osvEvent struct {
+	Introduced   string `json:"introduced"`
+	Fixed        string `json:"fixed"`
+	LastAffected string `json:"last_affected"`
+}
+
+// Check if vulnerable package is imported by an internal package
+func (v Vulnerability) IsImported() bool {
+	return len(v.ImportedBy) > 0
+}
+
+// Match module graph versions against a local OSV database snapshot (no network):
+// all OSV JSON entries under dbDir (e.g. the Go vulnerability database ID/ folder),
+// Go ecosystem, SEMVER ranges. Sorted by imported first, then module, ID
+func (mod Module) Vulnerabilities(dbDir string) ([]*Vulnerability, error) {
+	modules := make(map[string]*ExternalModule)
+	for _, m := range mod.ModuleGraph.Modules {
+		modules[m.Path] = m
+	}
+	// Imported external package => internal packages that import it (code files)
+	importers := make(map[string]*ds.Set[string])
+	for pkgName, hops := range mod.importHops(false) {
+		for _, hop := range hops {
+			if importers[hop.ImportPath] == nil {
+				importers[hop.ImportPath] = ds.NewSet[string]()
+			}
+			importers[hop.ImportPath].Add(pkgName)
+		}
+	}
+
+	vulns := make([]*Vulnerability, 0)
+	entryCount := 0
+	err := filepath.WalkDir(dbDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !endsWith(d.Name(), ".json") {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var entry osvEntry
+		if json.Unmarshal(data, &entry) != nil || entry.ID == "" {
+			return nil // not an OSV entry: index files
+		}
+		entryCount += 1
+		if entry.Withdrawn != "" {
+			return nil
+		}
+		for _, affected := range entry.Affected {
+			m, ok := modules[affected.Package.Name]
+			if !ok || !strings.EqualFold(affected.Package.Ecosystem, "Go") {
+				continue
+			}
+			isAffected := false
+			fixed := ds.NewSet[string]()
+			for _, r := range affected.Ranges {
+				if r.Type != "SEMVER" {
+					continue
+				}
+				inRange, fixedVersion := versionInRange(m.Version, r.Events)
+				if inRange && fixedVersion != "" {
+					fixed.Add(fixedVersion)
+				}
+				isAffected = isAffected || inRange
+			}
+			if !isAffected {
+				continue
+			}
+			vuln := &Vulnerability{
+				ID:       entry.ID,
+				Aliases:  lang.Ternary(entry.Aliases == nil, []string{}, entry.Aliases),
+				Summary:  entry.Summary,
+				Module:   m.Path,
+				Version:  m.Version,
+				Fixed:    fixed.Items(),
+				Packages: make([]string, 0),
+			}
+			slices.SortFunc(vuln.Fixed, compareVersions)
+			importedBy := ds.NewSet[string]()
+			for _, imp := range affected.EcosystemSpecific.Imports {
+				vuln.Packages = append(vuln.Packages, imp.Path)
+				if importers[imp.Path] != nil {
+					importedBy.AddItems(importers[imp.Path].Items())
+				}
+			}
+			if len(vuln.Packages) == 0 {
+				// Whole module: any of its packages
+				for importPath, users := range importers {
+					if importPath == m.Path || startsWith(importPath, m.Path+"/") {
+						importedBy.AddItems(users.Items())
+					}
+				}
+			}
+			vuln.ImportedBy = importedBy.Items()
+			slices.Sort(vuln.ImportedBy)
+			vulns = append(vulns, vuln)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if entryCount == 0 {
+		return nil, fmt.Errorf("no OSV entries found in %q", dbDir)
+	}
+	slices.SortFunc(vulns, func(a, b *Vulnerability) int {
+		isImported := func(v *Vulnerability) int { return lang.Ternary(v.IsImported(), 0, 1) }
+		return cmp.Or(
+			cmp.Compare(isImported(a), isImported(b)),
+			cmp.Compare(a.Module, b.Module),
+			cmp.Compare(a.ID, b.ID),
+		)
+	})
+	return vulns, nil
+}
+
+// Check if version is in a SEMVER range: events are in version order, and the version is in range
+// if it is at or after an introduced event, and before the fixed (or at most the last_affected)
+// event that closes that range. Returns the fixed version that closes its range (blank if none)
+func versionInRange(version string, events []osvEvent) (bool, string) {
+	inRange := false
+	for _, event := range events {
+		switch {
+		case event.Introduced != "":
+			inRange = inRange || event.Introduced == "0" || compareVersions(version, "v"+event.Introduced) >= 0
+		case event.Fixed != "":
+			if compareVersions(version, "v"+event.Fixed) < 0 {
+				// Later events are after the version
+				return inRange, lang.Ternary(inRange, "v"+event.Fixed, "")
+			}
+			inRange = false
+		case event.LastAffected != "":
+			if compareVersions(version, "v"+event.LastAffected) <= 0 {
+				return inRange, ""
+			}
+			inRange = false
+		}
+	}
+	return inRange, ""
+}
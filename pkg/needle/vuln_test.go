@@ -0,0 +1,46 @@
+package needle
+
+import "testing"
+
+func TestVersionInRange(t *testing.T) {
+	fixed := []osvEvent{{Introduced: "0"}, {Fixed: "1.2.0"}}
+	ranges := []osvEvent{{Introduced: "1.0.0"}, {Fixed: "1.1.0"}, {Introduced: "1.5.0"}, {Fixed: "1.5.3"}}
+	fromZero := []osvEvent{{Introduced: "0"}, {Fixed: "1.0.0"}, {Introduced: "2.0.0"}, {Fixed: "2.1.0"}}
+	lastAffected := []osvEvent{{Introduced: "1.0.0"}, {LastAffected: "1.2.0"}}
+	open := []osvEvent{{Introduced: "1.0.0"}}
+	tests := []struct {
+		name    string
+		version string
+		events  []osvEvent
+		want    bool
+		fixedIn string
+	}{
+		{"introduced 0", "v0.1.0", fixed, true, "v1.2.0"},
+		{"before fixed", "v1.1.9", fixed, true, "v1.2.0"},
+		{"prerelease of fixed", "v1.2.0-rc.1", fixed, true, "v1.2.0"},
+		{"at fixed", "v1.2.0", fixed, false, ""},
+		{"after fixed", "v1.3.0", fixed, false, ""},
+		{"before first range", "v0.9.0", ranges, false, ""},
+		{"at introduced", "v1.0.0", ranges, true, "v1.1.0"},
+		{"at first fixed", "v1.1.0", ranges, false, ""},
+		{"between ranges", "v1.4.9", ranges, false, ""},
+		{"at second introduced", "v1.5.0", ranges, true, "v1.5.3"},
+		{"in second range", "v1.5.2", ranges, true, "v1.5.3"},
+		{"at second fixed", "v1.5.3", ranges, false, ""},
+		{"after ranges", "v2.0.0", ranges, false, ""},
+		{"first range from 0", "v0.5.0", fromZero, true, "v1.0.0"},
+		{"between ranges from 0", "v1.5.0", fromZero, false, ""},
+		{"second range from 0", "v2.0.5", fromZero, true, "v2.1.0"},
+		{"at last_affected", "v1.2.0", lastAffected, true, ""},
+		{"after last_affected", "v1.2.1", lastAffected, false, ""},
+		{"before introduced", "v0.9.0", lastAffected, false, ""},
+		{"open range", "v9.0.0", open, true, ""},
+		{"no events", "v1.0.0", nil, false, ""},
+	}
+	for _, tt := range tests {
+		got, fixedIn := versionInRange(tt.version, tt.events)
+		if got != tt.want || fixedIn != tt.fixedIn {
+			t.Errorf("%s: versionInRange(%s) = %v, %q, want %v, %q", tt.name, tt.version, got, fixedIn, tt.want, tt.fixedIn)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/roidaradal/needle/pkg/needle"
+)
+
+const (
+	barWidth       = 30
+	redrawInterval = 100 * time.Millisecond
+)
+
+// Terminal progress bar, redrawn in place on a single line
+type progressBar struct {
+	out      io.Writer
+	lastDraw time.Time
+	isOpen   bool // bar drawn on current line, without trailing newline
+}
+
+// Create progress bar that draws on out
+func newProgressBar(out io.Writer) *progressBar {
+	return &progressBar{out: out}
+}
+
+// Draw progress bar for given progress event
+func (bar *progressBar) draw(e needle.ProgressEvent) {
+	isDone := e.Stage == needle.STAGE_DONE
+	// Throttle redraws, except for the final one
+	if !isDone && time.Since(bar.lastDraw) < redrawInterval {
+		return
+	}
+	bar.lastDraw = time.Now()
+
+	var line string
+	if e.Stage == needle.STAGE_GOMOD || e.Stage == needle.STAGE_MODULES || e.Stage == needle.STAGE_FOLDERS {
+		line = fmt.Sprintf("Discovering folders: %d", e.Folders)
+	} else {
+		filled := 0
+		if e.TotalPackages > 0 {
+			filled = barWidth * e.Packages / e.TotalPackages
+		}
+		line = fmt.Sprintf("[%s%s] %d/%d packages | %d/%d files | %.0f files/s | %s",
+			strings.Repeat("#", filled),
+			strings.Repeat("-", barWidth-filled),
+			e.Packages, e.TotalPackages,
+			e.Files, e.TotalFiles,
+			e.FilesPerSecond(),
+			e.Stage,
+		)
+	}
+	// \033[K clears the rest of the line
+	fmt.Fprintf(bar.out, "\r%s\033[K", line)
+	bar.isOpen = true
+	if isDone {
+		bar.finish()
+	}
+}
+
+// End the progress bar line, so that the next output starts on a new line
+func (bar *progressBar) finish() {
+	if bar == nil || !bar.isOpen {
+		return
+	}
+	fmt.Fprintln(bar.out)
+	bar.isOpen = false
+}
+
+// Check if file is a terminal (character device)
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// Get terminal width for text output: terminal columns, or $COLUMNS (0 = no limit)
+func terminalWidth(f *os.File) int {
+	if isTerminal(f) {
+		if width := terminalColumns(f); width > 0 {
+			return width
+		}
+	}
+	width, _ := strconv.Atoi(os.Getenv("COLUMNS"))
+	return width
+}
+
+// Check if colored output is enabled: terminal, and NO_COLOR not set (https://no-color.org)
+func isColorEnabled(f *os.File) bool {
+	return isTerminal(f) && os.Getenv("NO_COLOR") == ""
+}
@@ -0,0 +1,10 @@
+//go:build !linux && !darwin
+
+package main
+
+import "os"
+
+// Terminal column count is not queried on this platform: use $COLUMNS
+func terminalColumns(f *os.File) int {
+	return 0
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/roidaradal/needle/pkg/needle"
+)
+
+// Run vuln command: needle vuln [options] --db <osvDir> <modulePath>
+func runVuln(args []string) {
+	cfg := &config{logFormat: "text"}
+	flags := flag.NewFlagSet("vuln", flag.ExitOnError)
+	dbDir := flags.String("db", "", "folder of the local OSV database snapshot (JSON entries, e.g. the Go vulnerability database)")
+	isJSON := flags.Bool("json", false, "print vulnerabilities as JSON")
+	isImported := flags.Bool("imported", false, "only report vulnerabilities whose vulnerable package is imported")
+	flags.BoolVar(&cfg.verbose, "verbose", false, "show debug logs (stage durations, analyzed packages)")
+	flags.Usage = func() {
+		fmt.Println("Usage: needle vuln [options] --db <osvDir> <modulePath>")
+		fmt.Println("Exits with status 3 if a vulnerable package is imported")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	args = flags.Args()
+	if len(args) != 1 || *dbDir == "" {
+		flags.Usage()
+		os.Exit(1)
+	}
+	logger := newLogger(cfg)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	mod, err := needle.Analyze(ctx, args[0],
+		needle.WithLogger(logger),
+		needle.WithoutLicenses(),
+		needle.WithoutCallGraph(),
+	)
+	if err != nil {
+		fatal(logger, err)
+	}
+	vulns, err := mod.Vulnerabilities(*dbDir)
+	if err != nil {
+		fatal(logger, err)
+	}
+	importedCount := 0
+	for _, vuln := range vulns {
+		if vuln.IsImported() {
+			importedCount += 1
+		}
+	}
+	if *isImported {
+		vulns = vulns[:importedCount] // imported vulnerabilities are sorted first
+	}
+
+	if *isJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(vulns)
+		if err != nil {
+			fatal(logger, err)
+		}
+	} else if len(vulns) == 0 {
+		fmt.Println("No known vulnerabilities")
+	} else {
+		for _, vuln := range vulns {
+			fixed := "no fixed version"
+			if len(vuln.Fixed) > 0 {
+				fixed = "fixed in " + strings.Join(vuln.Fixed, ", ")
+			}
+			fmt.Printf("%s %s@%s (%s)\n", vuln.ID, vuln.Module, vuln.Version, fixed)
+			if vuln.Summary != "" {
+				fmt.Printf("    %s\n", vuln.Summary)
+			}
+			if len(vuln.Aliases) > 0 {
+				fmt.Printf("    aliases: %s\n", strings.Join(vuln.Aliases, ", "))
+			}
+			if len(vuln.Packages) > 0 {
+				fmt.Printf("    vulnerable packages: %s\n", strings.Join(vuln.Packages, ", "))
+			}
+			if vuln.IsImported() {
+				fmt.Printf("    imported by: %s\n", strings.Join(vuln.ImportedBy, ", "))
+			} else {
+				fmt.Println("    not imported: required module only")
+			}
+		}
+		fmt.Printf("%d vulnerabilities, %d imported\n", len(vulns), importedCount)
+	}
+
+	if importedCount > 0 {
+		os.Exit(3)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	"github.com/roidaradal/fn/io"
+	"github.com/roidaradal/needle/pkg/needle"
+)
+
+// Run why command: needle why [options] <modulePath> <from> <to>
+func runWhy(args []string) {
+	cfg := &config{logFormat: "text"}
+	flags := flag.NewFlagSet("why", flag.ExitOnError)
+	isAll := flags.Bool("all", false, "print all import chains, instead of one shortest chain")
+	limit := flags.Int("limit", 0, "with --all, stop after this many chains (0 = no limit)")
+	isTests := flags.Bool("tests", false, "also follow imports of test files")
+	isJSON := flags.Bool("json", false, "print import chains as JSON")
+	isHighlight := flags.Bool("highlight", false, "save the HTML report with the import chains highlighted in the dependency graph")
+	flags.BoolVar(&cfg.noOpen, "no-open", false, "with --highlight, print report path instead of opening it")
+	flags.BoolVar(&cfg.verbose, "verbose", false, "show debug logs (stage durations, analyzed packages)")
+	flags.Usage = func() {
+		fmt.Println("Usage: needle why [options] <modulePath> <fromPackage> <toPackage|externalImportPath>")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	args = flags.Args()
+	if len(args) != 3 {
+		flags.Usage()
+		os.Exit(1)
+	}
+	logger := newLogger(cfg)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	mod, err := needle.Analyze(ctx, args[0], needle.WithLogger(logger))
+	if err != nil {
+		fatal(logger, err)
+	}
+	chains, err := mod.ImportChains(needle.ChainQuery{
+		From:  args[1],
+		To:    args[2],
+		All:   *isAll,
+		Tests: *isTests,
+		Limit: *limit,
+	})
+	if err != nil {
+		fatal(logger, err)
+	}
+
+	if *isJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(chains)
+		if err != nil {
+			fatal(logger, err)
+		}
+	} else if len(chains) == 0 {
+		fmt.Printf("%s does not import %s\n", args[1], args[2])
+	} else {
+		for i, chain := range chains {
+			fmt.Printf("[%d] %s\n", i+1, strings.Join(chain.Packages(), " -> "))
+			for _, hop := range chain {
+				fmt.Printf("    %s:%d: import %q\n", hop.File, hop.Line, hop.ImportPath)
+			}
+		}
+	}
+
+	if !*isHighlight {
+		return
+	}
+	mod.Highlight(chains)
+	report, err := needle.BuildReport(mod)
+	if err != nil {
+		fatal(logger, err)
+	}
+	outputPath, err := needle.SaveReport(mod, report, "html")
+	if err != nil {
+		fatal(logger, err)
+	}
+	outputPath, _ = filepath.Abs(outputPath)
+	if cfg.noOpen {
+		fmt.Println(outputPath)
+		return
+	}
+	err = io.OpenFile(outputPath)
+	if err != nil {
+		fatal(logger, err)
+	}
+}